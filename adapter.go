@@ -2,7 +2,16 @@ package goscade
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/ognick/goscade/v2/internal/tlsutil"
 )
 
 // runFn defines a function type for running a delegate component.
@@ -15,6 +24,25 @@ type delegateNameProvider interface {
 	delegateName() string
 }
 
+// unwrapper is implemented by components that wrap another value.
+type unwrapper interface {
+	unwrap() any
+}
+
+// asOptional returns comp as T, looking through adapters to the value they
+// wrap. Optional interfaces such as Drainer and Reloader are resolved with it.
+func asOptional[T any](comp Component) (T, bool) {
+	if t, ok := comp.(T); ok {
+		return t, true
+	}
+	if w, ok := comp.(unwrapper); ok {
+		t, ok := w.unwrap().(T)
+		return t, ok
+	}
+	var zero T
+	return zero, false
+}
+
 // adapter wraps a delegate component and provides a way to run it
 // with custom logic while maintaining the Component interface.
 type adapter[T any] struct {
@@ -52,3 +80,222 @@ func (a *adapter[T]) delegateName() string {
 func (a *adapter[T]) Run(ctx context.Context, readinessProbe func(cause error)) error {
 	return a.run(ctx, a.delegate, readinessProbe)
 }
+
+// StartTimeout forwards the delegate's start timeout if it implements
+// StartTimeoutProvider, so wrapping a component does not hide its budget.
+func (a *adapter[T]) StartTimeout() time.Duration {
+	if p, ok := any(a.delegate).(StartTimeoutProvider); ok {
+		return p.StartTimeout()
+	}
+	return 0
+}
+
+// ShutdownTimeout forwards the delegate's shutdown timeout if it implements
+// ShutdownTimeoutProvider.
+func (a *adapter[T]) ShutdownTimeout() time.Duration {
+	if p, ok := any(a.delegate).(ShutdownTimeoutProvider); ok {
+		return p.ShutdownTimeout()
+	}
+	return 0
+}
+
+// unwrap returns the delegate, so optional interfaces such as Drainer and
+// Reloader are looked up on the wrapped value rather than declared by every
+// adapter.
+func (a *adapter[T]) unwrap() any {
+	return a.delegate
+}
+
+// NewTask creates a one-shot component, such as a database migration or a
+// cache warmer, that calls run once with delegate. The task is ready once run
+// returns nil, so the components depending on it start after it has
+// completed. Its Run then returns and the task reports
+// ComponentStatusCompleted; its completion does not stop the lifecycle or its
+// dependents. If run fails, its error is reported in PhaseRun and aborts the
+// startup. Restarting a task calls run again.
+func NewTask[T any](delegate T, run func(ctx context.Context, delegate T) error) Component {
+	return &task[T]{adapter: &adapter[T]{
+		delegate: delegate,
+		run: func(ctx context.Context, delegate T, readinessProbe func(cause error)) error {
+			if err := run(ctx, delegate); err != nil {
+				return err
+			}
+			readinessProbe(nil)
+			return nil
+		},
+	}}
+}
+
+// completer is implemented by components whose Run completes them when it
+// returns nil after they became ready, instead of closing them unexpectedly.
+type completer interface {
+	completes()
+}
+
+// task is the adapter returned by NewTask.
+type task[T any] struct {
+	*adapter[T]
+}
+
+func (*task[T]) completes() {}
+
+// StartStopper is implemented by services that start in the background and
+// are stopped explicitly, rather than blocking in Run.
+type StartStopper interface {
+	// Start starts the service. The service is ready once Start returns nil.
+	Start(ctx context.Context) error
+	// Stop stops the service before the deadline of ctx.
+	Stop(ctx context.Context) error
+}
+
+// ErrorNotifier can be implemented by a StartStopper that fails after it has
+// started. The first error received from Errors stops the component.
+type ErrorNotifier interface {
+	Errors() <-chan error
+}
+
+// ListenServer is implemented by servers that accept connections from a
+// net.Listener until they are shut down, such as *http.Server.
+type ListenServer interface {
+	// Serve accepts connections from ln and blocks until the server stops.
+	Serve(ln net.Listener) error
+	// Shutdown stops the server gracefully before the deadline of ctx and
+	// makes Serve return.
+	Shutdown(ctx context.Context) error
+}
+
+// FromStartStop adapts a service with Start and Stop methods. Start is called
+// when the component runs and reports readiness; once the component is asked
+// to stop, Stop is called with ShutdownContext. If the service implements
+// ErrorNotifier, an error it reports is returned from Run after Stop.
+func FromStartStop[T StartStopper](service T) Component {
+	return NewAdapter(service, func(ctx context.Context, service T, readinessProbe func(cause error)) error {
+		if err := service.Start(ctx); err != nil {
+			readinessProbe(err)
+			return err
+		}
+		readinessProbe(nil)
+
+		var errs <-chan error
+		if n, ok := any(service).(ErrorNotifier); ok {
+			errs = n.Errors()
+		}
+
+		var runErr error
+		select {
+		case <-ctx.Done():
+		case runErr = <-errs:
+		}
+		stopCtx, cancel := ShutdownContext(ctx)
+		defer cancel()
+		return errors.Join(runErr, service.Stop(stopCtx))
+	})
+}
+
+// FromCloser adapts a resource that is open already and released with Close,
+// such as a database handle. The component is ready immediately and closes
+// the resource once it is asked to stop.
+func FromCloser[T io.Closer](closer T) Component {
+	return NewAdapter(closer, func(ctx context.Context, closer T, readinessProbe func(cause error)) error {
+		readinessProbe(nil)
+		<-ctx.Done()
+		return closer.Close()
+	})
+}
+
+// FromListener adapts a server that serves connections from a net.Listener.
+// The component listens on network and address before it reports readiness,
+// so readiness means the port is open. Once the component is asked to stop,
+// Shutdown is called with ShutdownContext and Run waits for Serve to return.
+// If Serve returns earlier, its error is returned from Run.
+//
+// Servers such as *http.Server cannot serve again after Shutdown, so the
+// component runs only once: starting it again, for instance with Restart or
+// Reload, fails with NotRestartableError.
+func FromListener[T ListenServer](server T, network, address string) Component {
+	var served atomic.Bool
+	return NewAdapter(server, func(ctx context.Context, server T, readinessProbe func(cause error)) error {
+		return listenAndServe(ctx, &served, network, address, server, readinessProbe)
+	})
+}
+
+// FromHTTPServer adapts srv like FromListener, listening on srv.Addr over
+// TCP. If srv.TLSConfig holds a certificate when FromHTTPServer is called,
+// the server serves TLS with the certificates of its TLSConfig; otherwise it
+// serves plain HTTP. Like FromListener, the
+// component cannot be restarted.
+func FromHTTPServer(srv *http.Server) Component {
+	var server ListenServer = srv
+	addr := srv.Addr
+	if tlsutil.HasCertificate(srv.TLSConfig) {
+		server = tlsServer{srv}
+		if addr == "" {
+			addr = ":https"
+		}
+	} else if addr == "" {
+		addr = ":http"
+	}
+
+	var served atomic.Bool
+	return NewAdapter(srv, func(ctx context.Context, _ *http.Server, readinessProbe func(cause error)) error {
+		return listenAndServe(ctx, &served, "tcp", addr, server, readinessProbe)
+	})
+}
+
+// tlsServer serves TLS with the certificates of the server's TLSConfig.
+type tlsServer struct {
+	*http.Server
+}
+
+func (s tlsServer) Serve(ln net.Listener) error {
+	return s.ServeTLS(ln, "", "")
+}
+
+// listenAndServe listens on network and address and serves server, unless
+// server has already been served.
+func listenAndServe(
+	ctx context.Context,
+	served *atomic.Bool,
+	network, address string,
+	server ListenServer,
+	readinessProbe func(cause error),
+) error {
+	if served.Load() {
+		err := fmt.Errorf("%w: the server has already been shut down", NotRestartableError)
+		readinessProbe(err)
+		return err
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		readinessProbe(err)
+		return err
+	}
+	served.Store(true)
+	return ServeListener(ctx, ln, server, readinessProbe)
+}
+
+// ServeListener serves ln with server in the background, reports readiness
+// and, once ctx is done, shuts server down with ShutdownContext and waits for
+// Serve to return. If Serve returns earlier, its error is returned. It is the
+// core of FromListener for components that open the listener themselves.
+func ServeListener(ctx context.Context, ln net.Listener, server ListenServer, readinessProbe func(cause error)) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ln)
+	}()
+	readinessProbe(nil)
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopCtx, cancel := ShutdownContext(ctx)
+	defer cancel()
+	err := server.Shutdown(stopCtx)
+	if serveErr := <-served; !errors.Is(serveErr, http.ErrServerClosed) && !errors.Is(serveErr, net.ErrClosed) {
+		err = errors.Join(err, serveErr)
+	}
+	return err
+}
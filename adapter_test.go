@@ -0,0 +1,388 @@
+package goscade
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type startStopService struct {
+	mu       sync.Mutex
+	events   []string
+	startErr error
+	errs     chan error
+	deadline time.Duration
+}
+
+func (s *startStopService) record(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *startStopService) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.events...)
+}
+
+func (s *startStopService) Start(context.Context) error {
+	s.record("start")
+	return s.startErr
+}
+
+func (s *startStopService) Stop(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		s.deadline = time.Until(deadline)
+	}
+	s.record("stop")
+	return nil
+}
+
+type notifyingService struct {
+	startStopService
+}
+
+func (s *notifyingService) Errors() <-chan error {
+	return s.errs
+}
+
+type closerFunc func() error
+
+func (f *closerFunc) Close() error {
+	return (*f)()
+}
+
+func TestFromStartStop(t *testing.T) {
+	service := &startStopService{}
+	lc := NewLifecycle(&mockLogger{}, WithShutdownTimeout(time.Minute))
+	comp := FromStartStop(service)
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	assert.Equal(t, []string{"start"}, service.recorded())
+	cancel()
+	receiveLifecycleError(t, done)
+
+	assert.Equal(t, []string{"start", "stop"}, service.recorded())
+	assert.InDelta(t, time.Minute, service.deadline, float64(time.Second))
+	assert.Equal(t, "*goscade.startStopService", lc.(*lifecycle).componentName(comp))
+}
+
+func TestFromStartStop_StartError(t *testing.T) {
+	startErr := errors.New("bind failed")
+	service := &startStopService{startErr: startErr}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromStartStop(service))
+
+	err := lc.Run(context.Background(), nil)
+	require.ErrorIs(t, err, startErr)
+	componentErr := ComponentErrors(err)[0]
+	assert.Equal(t, PhaseReadiness, componentErr.Phase)
+	assert.Equal(t, []string{"start"}, service.recorded())
+}
+
+func TestFromStartStop_AsyncError(t *testing.T) {
+	asyncErr := errors.New("connection lost")
+	service := &notifyingService{startStopService{errs: make(chan error, 1)}}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromStartStop(service))
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	service.errs <- asyncErr
+
+	err := receiveLifecycleError(t, done)
+	require.ErrorIs(t, err, asyncErr)
+	assert.Equal(t, PhaseRun, ComponentErrors(err)[0].Phase)
+	assert.Equal(t, []string{"start", "stop"}, service.recorded())
+}
+
+func TestFromCloser(t *testing.T) {
+	closed := make(chan struct{})
+	closer := closerFunc(func() error {
+		close(closed)
+		return io.ErrClosedPipe
+	})
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromCloser(&closer))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	select {
+	case <-closed:
+		t.Fatal("closed before shutdown")
+	default:
+	}
+	cancel()
+
+	err := receiveLifecycleError(t, done)
+	<-closed
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Equal(t, PhaseShutdown, ComponentErrors(err)[0].Phase)
+}
+
+// listenServer records the listener it serves.
+type listenServer struct {
+	addrs chan net.Addr
+	stop  chan struct{}
+	once  sync.Once
+}
+
+func (s *listenServer) Serve(ln net.Listener) error {
+	s.addrs <- ln.Addr()
+	<-s.stop
+	return ln.Close()
+}
+
+func (s *listenServer) Shutdown(context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+func TestFromListener(t *testing.T) {
+	server := &listenServer{addrs: make(chan net.Addr, 1), stop: make(chan struct{})}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromListener(server, "tcp", "127.0.0.1:0"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	conn, err := net.Dial("tcp", (<-server.addrs).String())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	cancel()
+	assert.ErrorIs(t, receiveLifecycleError(t, done), context.Canceled)
+}
+
+func TestFromListener_ListenError(t *testing.T) {
+	server := &listenServer{addrs: make(chan net.Addr, 1), stop: make(chan struct{})}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromListener(server, "tcp", "256.0.0.1:0"))
+
+	err := lc.Run(context.Background(), nil)
+	require.Error(t, err)
+	assert.Equal(t, PhaseReadiness, ComponentErrors(err)[0].Phase)
+}
+
+func TestFromHTTPServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromHTTPServer(srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	resp, err := http.Get("http://" + addr)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok", string(body))
+
+	cancel()
+	err = receiveLifecycleError(t, done)
+	assert.Empty(t, ComponentErrors(err))
+	_, err = http.Get("http://" + addr)
+	assert.Error(t, err)
+}
+
+func TestFromHTTPServer_TLSConfigWithoutCertificateServesHTTP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	srv := &http.Server{Addr: addr, Handler: http.NotFoundHandler(), TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(FromHTTPServer(srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	resp, err := http.Get("http://" + addr)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestFromHTTPServer_RestartFails(t *testing.T) {
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+	comp := FromHTTPServer(srv)
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	err := lc.Restart(comp)
+	assert.ErrorIs(t, err, NotRestartableError)
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+func TestShutdownContext_WithoutLifecycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	cancel()
+
+	stopCtx, stop := ShutdownContext(ctx)
+	defer stop()
+	assert.NoError(t, stopCtx.Err())
+	assert.Equal(t, "acme", stopCtx.Value(tenantKey{}))
+	_, ok := stopCtx.Deadline()
+	assert.False(t, ok)
+}
+
+func TestShutdownContext_ComponentBudget(t *testing.T) {
+	deadlines := make(chan time.Duration, 1)
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&timeoutComponent{shutdown: 5 * time.Second, lifecycleErrorComponent: lifecycleErrorComponent{
+		name: "budgeted",
+		run: func(ctx context.Context, probe func(error)) error {
+			probe(nil)
+			<-ctx.Done()
+			stopCtx, cancel := ShutdownContext(ctx)
+			defer cancel()
+			deadline, _ := stopCtx.Deadline()
+			deadlines <- time.Until(deadline)
+			return nil
+		},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	receiveLifecycleError(t, done)
+	assert.InDelta(t, 5*time.Second, <-deadlines, float64(time.Second))
+}
+
+type migrator struct {
+	runs atomic.Int32
+	err  error
+}
+
+func TestNewTask_DependantsStartAfterCompletion(t *testing.T) {
+	events := &eventRecorder{}
+	m := &migrator{}
+	task := NewTask(m, func(ctx context.Context, m *migrator) error {
+		time.Sleep(10 * time.Millisecond)
+		m.runs.Add(1)
+		events.record("migrated")
+		return nil
+	})
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(task)
+	lc.Register(recordedComponent("api", events), task)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	assert.Equal(t, []string{"migrated", "api started"}, events.snapshot())
+	assert.Equal(t, "*goscade.migrator", lc.(*lifecycle).componentName(task))
+
+	require.NoError(t, lc.Restart(task))
+	assert.Equal(t, int32(2), m.runs.Load())
+	assert.Equal(t, []string{"migrated", "api started", "api stopped", "migrated", "api started"}, events.snapshot())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestNewTask_CompletesWithoutStoppingDependants(t *testing.T) {
+	var mu sync.Mutex
+	var taskStatuses []ComponentStatus
+	m := &migrator{}
+	task := NewTask(m, func(_ context.Context, m *migrator) error {
+		m.runs.Add(1)
+		return nil
+	})
+	lc := NewLifecycle(&mockLogger{}, WithEventHandler(func(event Event) {
+		if event.Component == "*goscade.migrator" && event.ComponentStatus != "" {
+			mu.Lock()
+			taskStatuses = append(taskStatuses, event.ComponentStatus)
+			mu.Unlock()
+		}
+	}))
+	events := &eventRecorder{}
+	api := recordedComponent("api", events)
+	lc.Register(task)
+	lc.Register(api, task)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.Eventually(t, func() bool {
+		return lc.Snapshot().Components[0].Status == ComponentStatusCompleted
+	}, time.Second, time.Millisecond)
+
+	snapshot := lc.Snapshot()
+	assert.Equal(t, LifecycleStatusReady, snapshot.Status)
+	assert.Equal(t, "api", snapshot.Components[1].Name)
+	assert.Equal(t, ComponentStatusReady, snapshot.Components[1].Status)
+	mu.Lock()
+	assert.Equal(t, []ComponentStatus{
+		ComponentStatusWaiting, ComponentStatusStarting, ComponentStatusReady, ComponentStatusCompleted,
+	}, taskStatuses)
+	mu.Unlock()
+
+	// Dependants started after the task has completed do not run it again
+	require.NoError(t, lc.Stop(api))
+	require.NoError(t, lc.Start(api))
+	assert.Equal(t, []string{"api started", "api stopped", "api started"}, events.snapshot())
+	assert.Equal(t, int32(1), m.runs.Load())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	mu.Lock()
+	assert.Equal(t, ComponentStatusCompleted, taskStatuses[len(taskStatuses)-1])
+	mu.Unlock()
+}
+
+func TestNewTask_FailureAbortsStartup(t *testing.T) {
+	events := &eventRecorder{}
+	m := &migrator{err: errors.New("dirty schema")}
+	task := NewTask(m, func(ctx context.Context, m *migrator) error {
+		return m.err
+	})
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(task)
+	lc.Register(recordedComponent("api", events), task)
+
+	err := lc.Run(context.Background(), nil)
+	require.ErrorIs(t, err, m.err)
+	componentErrs := ComponentErrors(err)
+	require.Len(t, componentErrs, 1)
+	assert.Equal(t, PhaseRun, componentErrs[0].Phase)
+	assert.Equal(t, "*goscade.migrator", componentErrs[0].Name)
+	assert.Empty(t, events.snapshot())
+}
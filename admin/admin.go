@@ -0,0 +1,236 @@
+// Package admin provides an http.Handler that exposes a running
+// goscade.Lifecycle for inspection and control.
+//
+// The handler serves the following endpoints, relative to where it is mounted:
+//
+//	GET  /status                    JSON status of the lifecycle and every component
+//	GET  /graph                     dependency graph as JSON
+//	GET  /graph.dot                 dependency graph in Graphviz DOT format
+//	GET  /graph.svg                 dependency graph rendered as SVG, colored by status
+//	GET  /events                    Server-Sent Events stream of status changes and errors
+//	POST /components/{name}/stop    stop a component and its dependents
+//	POST /components/{name}/start   start a component and its dependencies
+//	POST /components/{name}/restart restart a component and its dependents
+//	POST /shutdown                  shut the lifecycle down gracefully
+//
+// The POST endpoints are rejected with 403 Forbidden unless they are enabled
+// with WithToken or WithAuthorizer. Components are addressed by their display
+// name; a name shared by several components, such as two instances of the
+// same type, is rejected with 409 Conflict.
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", admin.NewHandler(lc, admin.WithToken(token))))
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// errAmbiguousName is returned when a component name matches several
+// components.
+var errAmbiguousName = errors.New("ambiguous component name")
+
+// Option configures the handler.
+type Option func(*handler)
+
+// WithAuthorizer enables the POST endpoints for requests for which authorize
+// returns true.
+func WithAuthorizer(authorize func(r *http.Request) bool) Option {
+	return func(h *handler) {
+		h.authorize = authorize
+	}
+}
+
+// WithToken enables the POST endpoints for requests carrying
+// "Authorization: Bearer <token>". It panics if token is empty, since an empty
+// token would accept any request with a bare "Bearer " header.
+func WithToken(token string) Option {
+	if token == "" {
+		panic("admin: empty token")
+	}
+	expected := []byte("Bearer " + token)
+	return WithAuthorizer(func(r *http.Request) bool {
+		return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) == 1
+	})
+}
+
+type handler struct {
+	lc        goscade.Lifecycle
+	authorize func(r *http.Request) bool
+	mux       *http.ServeMux
+}
+
+// NewHandler returns an http.Handler exposing lc.
+func NewHandler(lc goscade.Lifecycle, opts ...Option) http.Handler {
+	h := &handler{
+		lc:        lc,
+		authorize: func(*http.Request) bool { return false },
+		mux:       http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("GET /status", h.handleStatus)
+	h.mux.HandleFunc("GET /graph", h.handleGraph)
+	h.mux.HandleFunc("GET /graph.dot", h.handleGraphDOT)
+	h.mux.HandleFunc("GET /graph.svg", h.handleGraphSVG)
+	h.mux.HandleFunc("GET /events", h.handleEvents)
+	h.mux.HandleFunc("POST /components/{name}/stop", h.guard(h.componentAction(h.lc.Stop)))
+	h.mux.HandleFunc("POST /components/{name}/start", h.guard(h.componentAction(h.lc.Start)))
+	h.mux.HandleFunc("POST /components/{name}/restart", h.guard(h.componentAction(h.lc.Restart)))
+	h.mux.HandleFunc("POST /shutdown", h.guard(h.handleShutdown))
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.lc.Snapshot())
+}
+
+func (h *handler) handleGraph(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.lc.BuildGraph())
+}
+
+func (h *handler) handleGraphDOT(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	_, _ = w.Write([]byte(h.lc.BuildGraph().ToDOT()))
+}
+
+func (h *handler) handleGraphSVG(w http.ResponseWriter, _ *http.Request) {
+	statuses := make(map[string]goscade.ComponentStatus)
+	for _, comp := range h.lc.Snapshot().Components {
+		statuses[comp.Name] = comp.Status
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, _ = w.Write(renderSVG(h.lc.BuildGraph(), statuses))
+}
+
+func (h *handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	events := h.lc.Subscribe(r.Context())
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for event := range events {
+		data, err := json.Marshal(newEventMessage(event))
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// eventMessage is the JSON representation of an event in the event stream.
+type eventMessage struct {
+	goscade.Event
+	Phase goscade.Phase `json:"phase,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+func newEventMessage(event goscade.Event) eventMessage {
+	msg := eventMessage{Event: event}
+	if event.Err != nil {
+		msg.Phase = event.Err.Phase
+		msg.Error = event.Err.Err.Error()
+	}
+	return msg
+}
+
+// componentAction returns a handler applying action to the component named
+// in the path and responding with the resulting status.
+func (h *handler) componentAction(action func(goscade.Component) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comp, err := h.lookup(r.PathValue("name"))
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+
+		if err := action(comp); err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, h.lc.Snapshot())
+	}
+}
+
+func (h *handler) handleShutdown(w http.ResponseWriter, _ *http.Request) {
+	if err := h.lc.Shutdown(); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// guard rejects requests that are not authorized.
+func (h *handler) guard(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.authorize(r) {
+			writeError(w, http.StatusForbidden, errors.New("forbidden"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// lookup returns the registered component with the given display name. It
+// fails with errAmbiguousName if several components share the name, such as
+// two instances of the same type, rather than acting on an arbitrary one.
+func (h *handler) lookup(name string) (goscade.Component, error) {
+	var found []goscade.Component
+	for _, comp := range h.lc.Snapshot().Components {
+		if comp.Name == name {
+			found = append(found, comp.Component)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", goscade.UnregisteredComponentError, name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d components are named %s", errAmbiguousName, len(found), name)
+	}
+}
+
+// errorStatus maps an error returned by the lifecycle to an HTTP status code.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, goscade.LifecycleNotReadyError), errors.Is(err, errAmbiguousName):
+		return http.StatusConflict
+	case errors.Is(err, goscade.UnregisteredComponentError):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,265 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+type database struct{}
+
+func (*database) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+type api struct {
+	db *database
+}
+
+func (*api) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+// runLifecycle starts a lifecycle with a database and an api depending on it
+// and stops it when the test ends.
+func runLifecycle(t *testing.T) goscade.Lifecycle {
+	t.Helper()
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	db := &database{}
+	lc.Register(db)
+	lc.Register(&api{db: db})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = lc.Run(ctx, func(err error) { ready <- err })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.NoError(t, <-ready)
+	return lc
+}
+
+func TestHandler_Status(t *testing.T) {
+	h := NewHandler(runLifecycle(t))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"status": "ready",
+		"components": [
+			{"name": "*admin.api", "status": "ready", "dependencies": ["*admin.database"]},
+			{"name": "*admin.database", "status": "ready", "dependencies": []}
+		]
+	}`, rec.Body.String())
+}
+
+func TestHandler_Graph(t *testing.T) {
+	h := NewHandler(runLifecycle(t))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graph", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var graph goscade.Graph
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &graph))
+	assert.Equal(t, []goscade.GraphEdge{{From: "*admin.database", To: "*admin.api"}}, graph.Edges)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graph.dot", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"*admin.database" -> "*admin.api";`)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graph.svg", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "<svg "))
+	assert.Contains(t, rec.Body.String(), `<title>*admin.api: ready</title>`)
+	assert.Contains(t, rec.Body.String(), `fill="#c8e6c9"`)
+}
+
+func TestHandler_CompletedTask(t *testing.T) {
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(goscade.NewTask(&database{}, func(context.Context, *database) error { return nil }))
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = lc.Run(ctx, func(err error) { ready <- err })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	require.NoError(t, <-ready)
+	h := NewHandler(lc)
+
+	require.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+		return strings.Contains(rec.Body.String(), `"status":"completed"`)
+	}, time.Second, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graph.svg", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<title>*admin.database: completed</title>`)
+	assert.Contains(t, rec.Body.String(), `fill="#bbdefb"`)
+}
+
+func TestHandler_ControlRequiresAuthorization(t *testing.T) {
+	lc := runLifecycle(t)
+
+	rec := httptest.NewRecorder()
+	NewHandler(lc).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/components/*admin.api/stop", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	h := NewHandler(lc, WithToken("secret"))
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/shutdown", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/components/*admin.api/stop", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, goscade.LifecycleStatusReady, lc.Status())
+}
+
+func TestHandler_AmbiguousNameConflicts(t *testing.T) {
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(&api{})
+	lc.Register(&api{})
+
+	req := httptest.NewRequest(http.MethodPost, "/components/*admin.api/stop", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHandler(lc, WithToken("secret")).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ambiguous component name: 2 components are named *admin.api")
+}
+
+func TestWithToken_RejectsEmptyToken(t *testing.T) {
+	assert.PanicsWithValue(t, "admin: empty token", func() { WithToken("") })
+}
+
+func TestHandler_StopStartRestart(t *testing.T) {
+	lc := runLifecycle(t)
+	h := NewHandler(lc, WithToken("secret"))
+	post := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	statuses := func(rec *httptest.ResponseRecorder) map[string]goscade.ComponentStatus {
+		var snapshot goscade.Snapshot
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+		result := make(map[string]goscade.ComponentStatus)
+		for _, comp := range snapshot.Components {
+			result[comp.Name] = comp.Status
+		}
+		return result
+	}
+
+	rec := post("/components/*admin.database/stop")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]goscade.ComponentStatus{
+		"*admin.api":      goscade.ComponentStatusStopped,
+		"*admin.database": goscade.ComponentStatusStopped,
+	}, statuses(rec))
+
+	rec = post("/components/*admin.api/start")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, goscade.ComponentStatusReady, statuses(rec)["*admin.api"])
+
+	rec = post("/components/*admin.database/restart")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, goscade.ComponentStatusReady, statuses(rec)["*admin.database"])
+
+	rec = post("/components/unknown/stop")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error": "unregistered component: unknown"}`, rec.Body.String())
+
+	rec = post("/shutdown")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	require.Eventually(t, func() bool {
+		return lc.Status() == goscade.LifecycleStatusStopped
+	}, time.Second, time.Millisecond)
+
+	rec = post("/components/*admin.api/restart")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandler_Events(t *testing.T) {
+	lc := runLifecycle(t)
+	srv := httptest.NewServer(NewHandler(lc))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.NoError(t, lc.Stop(lc.Snapshot().Components[0].Component))
+
+	scanner := bufio.NewScanner(resp.Body)
+	var events []goscade.Event
+	for len(events) < 2 && scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event goscade.Event
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, "*admin.api", events[0].Component)
+	assert.Equal(t, goscade.ComponentStatusStopping, events[0].ComponentStatus)
+	assert.Equal(t, goscade.ComponentStatusStopped, events[1].ComponentStatus)
+}
+
+func TestNewEventMessage(t *testing.T) {
+	event := goscade.Event{
+		Component: "*admin.api",
+		Err:       &goscade.ComponentError{Name: "*admin.api", Phase: goscade.PhaseRun, Err: errors.New("connection lost")},
+	}
+	data, err := json.Marshal(newEventMessage(event))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"time": "0001-01-01T00:00:00Z",
+		"component": "*admin.api",
+		"phase": "run",
+		"error": "connection lost"
+	}`, string(data))
+}
@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+
+	"github.com/ognick/goscade/v2"
+)
+
+const (
+	svgMargin     = 20
+	svgNodeHeight = 32
+	svgHGap       = 24
+	svgVGap       = 56
+	svgCharWidth  = 7
+)
+
+// statusColors are the fill colors of nodes by component status.
+var statusColors = map[goscade.ComponentStatus]string{
+	goscade.ComponentStatusWaiting:   "#fff9c4",
+	goscade.ComponentStatusStarting:  "#fff9c4",
+	goscade.ComponentStatusReady:     "#c8e6c9",
+	goscade.ComponentStatusCompleted: "#bbdefb",
+	goscade.ComponentStatusDraining:  "#ffe0b2",
+	goscade.ComponentStatusStopping:  "#ffe0b2",
+	goscade.ComponentStatusStopped:   "#e0e0e0",
+	goscade.ComponentStatusFailed:    "#ffcdd2",
+}
+
+type svgNode struct {
+	x, y, width int
+}
+
+// renderSVG draws graph top-down with every component below the components it
+// depends on, filling each node with the color of its status.
+func renderSVG(graph goscade.Graph, statuses map[string]goscade.ComponentStatus) []byte {
+	// Place each node one layer below its deepest dependency. Relaxation is
+	// bounded by the number of nodes so that cycles terminate.
+	depth := make(map[string]int, len(graph.Nodes))
+	for i := 0; i < len(graph.Nodes); i++ {
+		changed := false
+		for _, edge := range graph.Edges {
+			if d := depth[edge.From] + 1; d > depth[edge.To] && d < len(graph.Nodes) {
+				depth[edge.To] = d
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var layers [][]string
+	for _, node := range graph.Nodes {
+		d := depth[node.ID]
+		for len(layers) <= d {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], node.ID)
+	}
+
+	width := 0
+	layerWidths := make([]int, len(layers))
+	for i, layer := range layers {
+		sort.Strings(layer)
+		for j, id := range layer {
+			if j > 0 {
+				layerWidths[i] += svgHGap
+			}
+			layerWidths[i] += nodeWidth(id)
+		}
+		width = max(width, layerWidths[i])
+	}
+
+	nodes := make(map[string]svgNode, len(graph.Nodes))
+	for i, layer := range layers {
+		x := svgMargin + (width-layerWidths[i])/2
+		for _, id := range layer {
+			nodes[id] = svgNode{x: x, y: svgMargin + i*(svgNodeHeight+svgVGap), width: nodeWidth(id)}
+			x += nodeWidth(id) + svgHGap
+		}
+	}
+
+	edges := append([]goscade.GraphEdge(nil), graph.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+
+	var b bytes.Buffer
+	height := 2*svgMargin + len(layers)*svgNodeHeight + max(len(layers)-1, 0)*svgVGap
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		width+2*svgMargin, height)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto">` +
+		`<path d="M0,0 L10,5 L0,10 z" fill="#555"/></marker></defs>` + "\n")
+	for _, edge := range edges {
+		from, to := nodes[edge.From], nodes[edge.To]
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#555" marker-end="url(#arrow)"/>`+"\n",
+			from.x+from.width/2, from.y+svgNodeHeight, to.x+to.width/2, to.y)
+	}
+	for _, layer := range layers {
+		for _, id := range layer {
+			node := nodes[id]
+			fill, ok := statusColors[statuses[id]]
+			if !ok {
+				fill = "#ffffff"
+			}
+			name := html.EscapeString(id)
+			fmt.Fprintf(&b, `<g><title>%s: %s</title>`, name, html.EscapeString(string(statuses[id])))
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" stroke="#333"/>`,
+				node.x, node.y, node.width, svgNodeHeight, fill)
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="central">%s</text></g>`+"\n",
+				node.x+node.width/2, node.y+svgNodeHeight/2, name)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// nodeWidth returns the width of the box drawn for a component name.
+func nodeWidth(name string) int {
+	return max(80, 2*svgHGap+len(name)*svgCharWidth)
+}
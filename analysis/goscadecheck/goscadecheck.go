@@ -0,0 +1,367 @@
+// Package goscadecheck defines an analyzer that reports likely goscade
+// wiring mistakes without running the program.
+//
+// The analyzer reconstructs the component graph of a package from its
+// Register and Link call sites and the types implementing goscade.Component,
+// and reports:
+//
+//   - Register, Link or implicit dependencies passed as non-pointer values,
+//     which panic at runtime;
+//   - fields of registered components that hold a component type of which
+//     no value is registered, so the dependency is never started. Values
+//     registered by imported packages count, as they are exported as facts.
+//     The check is skipped if the package or an imported package registers
+//     interface values, whose types are unknown;
+//   - goscade struct tags that have no effect, either because the value is
+//     unknown or because the field can never reference a component;
+//   - Run implementations that never use their readinessProbe parameter,
+//     so the component can never become ready.
+//
+// Components are recognized by their Run method, so packages that declare
+// components without importing goscade are checked too.
+//
+// It can be run standalone or under go vet:
+//
+//	go vet -vettool=$(which goscadecheck) ./...
+package goscadecheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// goscadePath is the import path of the goscade package.
+const goscadePath = "github.com/ognick/goscade/v2"
+
+// Analyzer reports likely goscade wiring mistakes.
+var Analyzer = &analysis.Analyzer{
+	Name:      "goscadecheck",
+	Doc:       "report likely goscade component wiring mistakes",
+	URL:       "https://pkg.go.dev/github.com/ognick/goscade/v2/analysis/goscadecheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(registrations)},
+}
+
+// registrations is a package fact listing the component types registered by
+// a package, so that the packages importing it know they are registered.
+type registrations struct {
+	// Types are the registered types, qualified with their package paths.
+	Types []string
+	// Dynamic is set if the package registers interface values.
+	Dynamic bool
+}
+
+func (*registrations) AFact() {}
+
+func (r *registrations) String() string {
+	return fmt.Sprintf("registrations(%v, dynamic=%t)", r.Types, r.Dynamic)
+}
+
+// checker holds the per-package analysis state.
+type checker struct {
+	pass *analysis.Pass
+	// registered maps every type passed to Register/Link to its first call
+	// site. Keys are compared with types.Identical: every &T{} expression and
+	// every field type has its own *types.Pointer.
+	registered typeutil.Map
+	// dynamic is set once an interface value is registered.
+	dynamic bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{pass: pass}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.CallExpr)(nil), (*ast.StructType)(nil), (*ast.FuncDecl)(nil)}
+	ins.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			c.checkCall(n)
+		case *ast.StructType:
+			c.checkTags(n)
+		case *ast.FuncDecl:
+			c.checkRun(n)
+		}
+	})
+
+	c.checkUnregisteredFields()
+	c.exportRegistrations()
+	return nil, nil
+}
+
+// isComponent reports whether t implements goscade.Component, that is,
+// whether its method set has Run(context.Context, func(error)) error. The
+// method is matched structurally because packages declaring components often
+// import neither goscade nor a package that does.
+func isComponent(t types.Type) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, "Run")
+	if sel == nil {
+		return false
+	}
+	sig := sel.Obj().Type().(*types.Signature)
+	if sig.Variadic() || sig.Params().Len() != 2 || sig.Results().Len() != 1 || !isError(sig.Results().At(0).Type()) {
+		return false
+	}
+
+	ctx, ok := types.Unalias(sig.Params().At(0).Type()).(*types.Named)
+	if !ok || ctx.Obj().Pkg() == nil || ctx.Obj().Pkg().Path() != "context" || ctx.Obj().Name() != "Context" {
+		return false
+	}
+	probe, ok := sig.Params().At(1).Type().Underlying().(*types.Signature)
+	return ok && !probe.Variadic() && probe.Params().Len() == 1 && probe.Results().Len() == 0 &&
+		isError(probe.Params().At(0).Type())
+}
+
+// isError reports whether t is the predeclared error type.
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// registrationArgs returns the component arguments of a Register or Link
+// call: the registered component, and for Register its implicit dependencies.
+// It returns nil if call is not a goscade registration, or if it is made by
+// goscade itself on behalf of its callers.
+func (c *checker) registrationArgs(call *ast.CallExpr) []ast.Expr {
+	if c.pass.Pkg.Path() == goscadePath {
+		return nil
+	}
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != goscadePath {
+		return nil
+	}
+
+	sig := fn.Type().(*types.Signature)
+	args := call.Args
+	if sig.Recv() == nil {
+		// Package-level helpers take the lifecycle as the first argument.
+		if len(args) == 0 {
+			return nil
+		}
+		args = args[1:]
+	}
+
+	switch fn.Name() {
+	case "Register":
+		if call.Ellipsis.IsValid() {
+			return args[:1]
+		}
+		return args
+	case "Link":
+		if len(args) == 0 {
+			return nil
+		}
+		return args[:1]
+	}
+	return nil
+}
+
+// checkCall records registered component types and reports non-pointer
+// registrations.
+func (c *checker) checkCall(call *ast.CallExpr) {
+	fn, _ := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	for _, arg := range c.registrationArgs(call) {
+		t := c.pass.TypesInfo.TypeOf(arg)
+		if t == nil || isNil(c.pass.TypesInfo, arg) {
+			continue
+		}
+
+		switch t.Underlying().(type) {
+		case *types.Pointer:
+			if c.registered.At(t) == nil {
+				c.registered.Set(t, call)
+			}
+		case *types.Interface:
+			c.dynamic = true
+		default:
+			c.pass.Reportf(arg.Pos(), "%s called with non-pointer component of type %s; it panics at runtime",
+				fn.Name(), types.TypeString(t, types.RelativeTo(c.pass.Pkg)))
+		}
+	}
+}
+
+// checkTags reports goscade struct tags that have no effect.
+func (c *checker) checkTags(st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		value, ok := reflect.StructTag(raw).Lookup("goscade")
+		if !ok {
+			continue
+		}
+
+		if value != "ignore" {
+			c.pass.Reportf(field.Tag.Pos(), "unknown goscade tag value %q; only \"ignore\" is supported", value)
+			continue
+		}
+
+		t := c.pass.TypesInfo.TypeOf(field.Type)
+		if t != nil && !c.mayReferenceComponent(t, make(map[types.Type]bool)) {
+			c.pass.Reportf(field.Tag.Pos(), "goscade:\"ignore\" has no effect: a field of type %s cannot reference a component",
+				types.TypeString(t, types.RelativeTo(c.pass.Pkg)))
+		}
+	}
+}
+
+// mayReferenceComponent reports whether a value of type t can reference a
+// goscade component that dependency analysis would discover.
+func (c *checker) mayReferenceComponent(t types.Type, visited map[types.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	if isComponent(t) {
+		return true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		return true
+	case *types.Pointer:
+		return c.mayReferenceComponent(u.Elem(), visited)
+	case *types.Slice:
+		return c.mayReferenceComponent(u.Elem(), visited)
+	case *types.Array:
+		return c.mayReferenceComponent(u.Elem(), visited)
+	case *types.Map:
+		return c.mayReferenceComponent(u.Key(), visited) || c.mayReferenceComponent(u.Elem(), visited)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if c.mayReferenceComponent(u.Field(i).Type(), visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkRun reports Run methods implementing goscade.Component that never
+// use their readinessProbe parameter.
+func (c *checker) checkRun(decl *ast.FuncDecl) {
+	if decl.Recv == nil || decl.Name.Name != "Run" || decl.Body == nil {
+		return
+	}
+
+	fn, ok := c.pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	recv := fn.Type().(*types.Signature).Recv().Type()
+	if !isComponent(recv) && !isComponent(types.NewPointer(recv)) {
+		return
+	}
+
+	probe := probeParam(decl)
+	if probe == nil {
+		c.pass.Reportf(decl.Name.Pos(), "Run ignores its readiness probe; the component can never become ready")
+		return
+	}
+
+	obj := c.pass.TypesInfo.Defs[probe]
+	used := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && c.pass.TypesInfo.Uses[id] == obj {
+			used = true
+		}
+		return !used
+	})
+	if !used {
+		c.pass.Reportf(probe.Pos(), "Run never uses its readiness probe %s; the component can never become ready", probe.Name)
+	}
+}
+
+// probeParam returns the identifier of the second Run parameter, or nil if
+// it is unnamed or blank.
+func probeParam(decl *ast.FuncDecl) *ast.Ident {
+	var names []*ast.Ident
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, nil)
+		}
+		names = append(names, field.Names...)
+	}
+
+	if len(names) < 2 || names[1] == nil || names[1].Name == "_" {
+		return nil
+	}
+	return names[1]
+}
+
+// checkUnregisteredFields reports fields of registered components that hold
+// a concrete component type of which no value is registered in the package
+// or in the packages it imports. It reports nothing if any of them registers
+// interface values, as any component may have been registered through them.
+func (c *checker) checkUnregisteredFields() {
+	imported := make(map[string]bool)
+	for _, fact := range c.pass.AllPackageFacts() {
+		regs := fact.Fact.(*registrations)
+		if regs.Dynamic {
+			return
+		}
+		for _, t := range regs.Types {
+			imported[t] = true
+		}
+	}
+	if c.dynamic {
+		return
+	}
+
+	c.registered.Iterate(func(t types.Type, call interface{}) {
+		st, ok := t.Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct)
+		if !ok {
+			return
+		}
+
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if reflect.StructTag(st.Tag(i)).Get("goscade") == "ignore" {
+				continue
+			}
+			if _, isPtr := field.Type().Underlying().(*types.Pointer); !isPtr || !isComponent(field.Type()) {
+				continue
+			}
+			if c.registered.At(field.Type()) != nil || imported[types.TypeString(field.Type(), nil)] {
+				continue
+			}
+
+			c.pass.Reportf(call.(ast.Node).Pos(), "field %s of %s holds component type %s, but no value of that type is registered",
+				field.Name(),
+				types.TypeString(t, types.RelativeTo(c.pass.Pkg)),
+				types.TypeString(field.Type(), types.RelativeTo(c.pass.Pkg)))
+		}
+	})
+}
+
+// exportRegistrations exports the types registered by the package as a
+// registrations fact.
+func (c *checker) exportRegistrations() {
+	regs := &registrations{Dynamic: c.dynamic}
+	c.registered.Iterate(func(t types.Type, _ interface{}) {
+		regs.Types = append(regs.Types, types.TypeString(t, nil))
+	})
+	if len(regs.Types) == 0 && !regs.Dynamic {
+		return
+	}
+	sort.Strings(regs.Types)
+	c.pass.ExportPackageFact(regs)
+}
+
+// isNil reports whether expr is the predeclared nil.
+func isNil(info *types.Info, expr ast.Expr) bool {
+	tv, ok := info.Types[expr]
+	return ok && tv.IsNil()
+}
@@ -0,0 +1,13 @@
+package goscadecheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ognick/goscade/v2/analysis/goscadecheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), goscadecheck.Analyzer, "wiring", "components", "storage", "dynamic")
+}
@@ -0,0 +1,31 @@
+// Package components declares components without importing goscade.
+package components
+
+import "context"
+
+type Queue struct {
+	Retries int `goscade:"ignore"` // want `goscade:"ignore" has no effect: a field of type int cannot reference a component`
+}
+
+func (q *Queue) Run(ctx context.Context, probe func(error)) error { // want `Run never uses its readiness probe probe`
+	<-ctx.Done()
+	return nil
+}
+
+type Mailer struct {
+	Queue *Queue
+}
+
+func (m *Mailer) Run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+// Runner has a Run method that is not a component's.
+type Runner struct{}
+
+func (r *Runner) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,36 @@
+package dynamic // want package:`registrations\(\[\*dynamic.Service\], dynamic=true\)`
+
+import (
+	"context"
+
+	"github.com/ognick/goscade/v2"
+)
+
+type Database struct{}
+
+func (d *Database) Run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+type Service struct {
+	DB *Database
+}
+
+func (s *Service) Run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+func newComponent() goscade.Component {
+	return &Database{}
+}
+
+func Wire(lc goscade.Lifecycle) {
+	comp := newComponent()
+	lc.Register(&Service{DB: comp.(*Database)})
+	lc.Register(comp)
+	lc.Register(nil)
+}
@@ -0,0 +1,23 @@
+// Package goscade is a minimal stub of the goscade API used by analyzer tests.
+package goscade
+
+import "context"
+
+type Component interface {
+	Run(ctx context.Context, readinessProbe func(cause error)) error
+}
+
+type Lifecycle interface {
+	Register(component Component, implicitDeps ...Component)
+	Link(component Component, deps ...any)
+}
+
+func Register[T Component](lc Lifecycle, component T, implicitDeps ...Component) T {
+	lc.Register(component, implicitDeps...)
+	return component
+}
+
+func Link[T Component](lc Lifecycle, component T, deps ...any) T {
+	lc.Link(component, deps...)
+	return component
+}
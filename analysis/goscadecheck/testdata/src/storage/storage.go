@@ -0,0 +1,22 @@
+package storage // want package:`registrations\(\[\*storage.Database\], dynamic=false\)`
+
+import (
+	"context"
+
+	"github.com/ognick/goscade/v2"
+)
+
+type Database struct{}
+
+func (d *Database) Run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+// Register registers a Database with lc.
+func Register(lc goscade.Lifecycle) *Database {
+	db := &Database{}
+	lc.Register(db)
+	return db
+}
@@ -0,0 +1,98 @@
+package wiring // want package:`registrations\(\[\*wiring.Archive \*wiring.Database \*wiring.Repository \*wiring.Service\], dynamic=false\)`
+
+import (
+	"context"
+
+	"github.com/ognick/goscade/v2"
+	"storage"
+)
+
+type Database struct{}
+
+func (d *Database) Run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+type Cache struct{}
+
+func (c *Cache) Run(ctx context.Context, probe func(error)) error { // want `Run never uses its readiness probe probe`
+	<-ctx.Done()
+	return nil
+}
+
+type Broker struct{}
+
+func (b *Broker) Run(ctx context.Context, _ func(error)) error { // want `Run ignores its readiness probe;`
+	<-ctx.Done()
+	return nil
+}
+
+type Value struct{}
+
+func (v Value) Run(ctx context.Context, readinessProbe func(error)) error {
+	go readinessProbe(nil)
+	return nil
+}
+
+type Client struct {
+	Conn interface{ Close() error }
+}
+
+type Repository struct {
+	DB *Database
+}
+
+func (r *Repository) Run(ctx context.Context, readinessProbe func(error)) error {
+	return run(ctx, readinessProbe)
+}
+
+type Archive struct {
+	Store *storage.Database
+}
+
+func (a *Archive) Run(ctx context.Context, readinessProbe func(error)) error {
+	return run(ctx, readinessProbe)
+}
+
+type Service struct {
+	DB      *Database
+	Cache   *Cache
+	Broker  *Broker  `goscade:"ignore"`
+	Client  *Client  `goscade:"ignore"`
+	Retries int      `goscade:"ignore"` // want `goscade:"ignore" has no effect: a field of type int cannot reference a component`
+	Name    string   `goscade:"skip"`   // want `unknown goscade tag value "skip"`
+	Hooks   []func() `goscade:"ignore"` // want `goscade:"ignore" has no effect`
+}
+
+func (s *Service) Run(ctx context.Context, readinessProbe func(error)) error {
+	return run(ctx, readinessProbe)
+}
+
+func run(ctx context.Context, readinessProbe func(error)) error {
+	readinessProbe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+func Wire(lc goscade.Lifecycle) {
+	db := &Database{}
+	service := &Service{DB: db, Cache: &Cache{}}
+
+	lc.Register(db)
+	goscade.Register(lc, service) // want `field Cache of \*Service holds component type \*Cache, but no value of that type is registered`
+	lc.Register(Value{})          // want `Register called with non-pointer component of type Value; it panics at runtime`
+	goscade.Register(lc, Value{}) // want `Register called with non-pointer component of type Value`
+	lc.Register(db, Value{})      // want `Register called with non-pointer component of type Value`
+	goscade.Link(lc, Value{}, db) // want `Link called with non-pointer component of type Value`
+}
+
+func WireInline(lc goscade.Lifecycle) {
+	lc.Register(&Repository{DB: &Database{}})
+	lc.Register(&Database{})
+}
+
+func WireStorage(lc goscade.Lifecycle) {
+	lc.Register(&Archive{Store: storage.Register(lc)})
+}
@@ -0,0 +1,23 @@
+module github.com/ognick/goscade/v2/cmd
+
+go 1.22.0
+
+require (
+	github.com/ognick/goscade/v2 v2.0.0
+	github.com/ognick/goscade/v2/analysis v0.0.0
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/ognick/goscade/v2 => ../
+	github.com/ognick/goscade/v2/analysis => ../analysis
+)
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// componentDirective marks a constructor that "goscade gen" wires.
+const componentDirective = "//goscade:component"
+
+// goscadePath is the import path of the goscade package.
+const goscadePath = "github.com/ognick/goscade/v2"
+
+// provider is an annotated constructor of a component.
+type provider struct {
+	fn        *types.Func
+	result    types.Type
+	withError bool
+	varName   string
+	// args holds, for every constructor parameter, either the provider that
+	// supplies it or nil if it is an input of the generated function.
+	args []*provider
+}
+
+// input is a parameter of the generated wiring function.
+type input struct {
+	typ  types.Type
+	name string
+}
+
+// generator builds wiring code for a single package.
+type generator struct {
+	pkg       *packages.Package
+	funcName  string
+	component *types.Interface
+	providers []*provider
+	inputs    []*input
+	imports   map[string]string // import path -> package name
+}
+
+func runGen(e *env, args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	output := flags.String("o", "goscade_gen.go", "output file name, relative to the package directory")
+	funcName := flags.String("func", "Wire", "name of the generated wiring function")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return errUsage
+	}
+
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	code, err := generate(dir, *funcName, *output)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return os.WriteFile(path, code, 0o644) //nolint:gosec // generated source is world-readable
+}
+
+// generate loads the package in dir and returns formatted wiring code.
+// The output file, relative to dir unless absolute, is excluded from loading
+// so that regeneration does not see the previous result.
+func generate(dir, funcName, output string) ([]byte, error) {
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			if abs, err := filepath.Abs(filename); err == nil && abs == output {
+				src = []byte("package " + packageClause(src))
+			}
+			return parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+		},
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("failed to load %s: %v", dir, pkg.Errors[0])
+	}
+
+	g := &generator{
+		pkg:      pkg,
+		funcName: funcName,
+		imports:  map[string]string{goscadePath: "goscade"},
+	}
+	if err := g.collect(); err != nil {
+		return nil, err
+	}
+	if err := g.resolve(); err != nil {
+		return nil, err
+	}
+	ordered, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+	return g.render(ordered)
+}
+
+// collect finds every constructor annotated with componentDirective.
+func (g *generator) collect() error {
+	if obj, ok := lookupComponent(g.pkg.Types); ok {
+		g.component = obj
+	}
+
+	for _, file := range g.pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !hasDirective(fn.Doc) {
+				continue
+			}
+
+			p, err := g.newProvider(fn)
+			if err != nil {
+				return fmt.Errorf("%s: %w", g.pkg.Fset.Position(fn.Pos()), err)
+			}
+			g.providers = append(g.providers, p)
+		}
+	}
+
+	if len(g.providers) == 0 {
+		return fmt.Errorf("no %s constructors found in %s", componentDirective, g.pkg.PkgPath)
+	}
+	return nil
+}
+
+// lookupComponent returns goscade.Component if pkg imports goscade.
+func lookupComponent(pkg *types.Package) (*types.Interface, bool) {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() != goscadePath {
+			continue
+		}
+		if obj, ok := imp.Scope().Lookup("Component").(*types.TypeName); ok {
+			iface, ok := obj.Type().Underlying().(*types.Interface)
+			return iface, ok
+		}
+	}
+	return nil, false
+}
+
+// hasDirective reports whether doc contains componentDirective.
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == componentDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// newProvider validates an annotated constructor.
+func (g *generator) newProvider(decl *ast.FuncDecl) (*provider, error) {
+	fn := g.pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+	sig := fn.Type().(*types.Signature)
+	switch {
+	case decl.Recv != nil:
+		return nil, fmt.Errorf("%s: constructor must be a function, not a method", fn.Name())
+	case sig.TypeParams().Len() > 0:
+		return nil, fmt.Errorf("%s: generic constructors are not supported", fn.Name())
+	case sig.Variadic():
+		return nil, fmt.Errorf("%s: variadic constructors are not supported", fn.Name())
+	}
+
+	results := sig.Results()
+	p := &provider{fn: fn}
+	switch {
+	case results.Len() == 1:
+	case results.Len() == 2 && types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()):
+		p.withError = true
+	default:
+		return nil, fmt.Errorf("%s: constructor must return a component and an optional error", fn.Name())
+	}
+
+	p.result = results.At(0).Type()
+	if g.component == nil || !types.Implements(p.result, g.component) {
+		return nil, fmt.Errorf("%s: %s does not implement goscade.Component", fn.Name(), g.typeString(p.result))
+	}
+	if _, isIface := p.result.Underlying().(*types.Interface); !isIface {
+		if _, isPtr := p.result.Underlying().(*types.Pointer); !isPtr {
+			return nil, fmt.Errorf("%s: component %s must be a pointer", fn.Name(), g.typeString(p.result))
+		}
+	}
+	return p, nil
+}
+
+// resolve matches every constructor parameter to the provider producing a
+// value assignable to it. Unmatched parameters become inputs of the
+// generated function, unless they are components: the generated code could
+// neither register them nor declare them as dependencies. A matched
+// parameter is registered as a dependency even if the constructor does not
+// keep it, so the generated graph may have edges that reflection would not
+// find.
+func (g *generator) resolve() error {
+	for _, p := range g.providers {
+		params := p.fn.Type().(*types.Signature).Params()
+		p.args = make([]*provider, params.Len())
+		for i := 0; i < params.Len(); i++ {
+			param := params.At(i).Type()
+			var matches []*provider
+			for _, candidate := range g.providers {
+				if candidate != p && types.AssignableTo(candidate.result, param) {
+					matches = append(matches, candidate)
+				}
+			}
+
+			switch len(matches) {
+			case 0:
+				if g.isComponentInput(param) {
+					return fmt.Errorf("%s: parameter %s of type %s is a component that no %s constructor provides",
+						p.fn.Name(), paramName(params.At(i), i), g.typeString(param), componentDirective)
+				}
+				g.addInput(param)
+			case 1:
+				p.args[i] = matches[0]
+			default:
+				names := make([]string, 0, len(matches))
+				for _, m := range matches {
+					names = append(names, m.fn.Name())
+				}
+				return fmt.Errorf("%s: parameter %d of type %s is provided by several constructors: %s",
+					p.fn.Name(), i, g.typeString(param), strings.Join(names, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// isComponentInput reports whether param is a concrete component type, which
+// cannot be passed in as an input of the generated function.
+func (g *generator) isComponentInput(param types.Type) bool {
+	if _, isIface := param.Underlying().(*types.Interface); isIface {
+		return false
+	}
+	return types.Implements(param, g.component)
+}
+
+// paramName returns the name of param, or its index if it is unnamed.
+func paramName(param *types.Var, i int) string {
+	if param.Name() == "" || param.Name() == "_" {
+		return strconv.Itoa(i)
+	}
+	return param.Name()
+}
+
+// addInput records param as an input of the generated function.
+func (g *generator) addInput(param types.Type) {
+	for _, in := range g.inputs {
+		if types.Identical(in.typ, param) {
+			return
+		}
+	}
+	g.inputs = append(g.inputs, &input{typ: param})
+}
+
+// order sorts providers so that every provider follows its dependencies.
+// Ties keep declaration order.
+func (g *generator) order() ([]*provider, error) {
+	ordered := make([]*provider, 0, len(g.providers))
+	state := make(map[*provider]int) // 1: visiting, 2: done
+	var visit func(p *provider, chain []string) error
+	visit = func(p *provider, chain []string) error {
+		chain = append(chain, p.fn.Name())
+		switch state[p] {
+		case 1:
+			return fmt.Errorf("circular dependency: %s", strings.Join(chain, " -> "))
+		case 2:
+			return nil
+		}
+
+		state[p] = 1
+		for _, dep := range p.args {
+			if dep == nil {
+				continue
+			}
+			if err := visit(dep, chain); err != nil {
+				return err
+			}
+		}
+		state[p] = 2
+		ordered = append(ordered, p)
+		return nil
+	}
+
+	for _, p := range g.providers {
+		if err := visit(p, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// render produces the formatted source of the wiring function.
+func (g *generator) render(ordered []*provider) ([]byte, error) {
+	used := map[string]bool{"lc": true, "err": true, "goscade": true}
+	for _, p := range ordered {
+		p.varName = uniqueName(used, varName(strings.TrimPrefix(p.fn.Name(), "New")))
+	}
+	for _, in := range g.inputs {
+		in.name = uniqueName(used, varName(typeBaseName(in.typ)))
+	}
+
+	var body bytes.Buffer
+	for _, p := range ordered {
+		args := make([]string, len(p.args))
+		deps := make([]string, 0)
+		for i, dep := range p.args {
+			if dep != nil {
+				args[i] = dep.varName
+				deps = appendUnique(deps, dep.varName)
+				continue
+			}
+			param := p.fn.Type().(*types.Signature).Params().At(i).Type()
+			args[i] = g.inputName(param)
+		}
+
+		call := fmt.Sprintf("%s(%s)", p.fn.Name(), strings.Join(args, ", "))
+		if p.withError {
+			fmt.Fprintf(&body, "\t%s, err := %s\n\tif err != nil {\n\t\treturn err\n\t}\n", p.varName, call)
+		} else {
+			fmt.Fprintf(&body, "\t%s := %s\n", p.varName, call)
+		}
+		fmt.Fprintf(&body, "\tlc.Register(%s)\n\n", strings.Join(append([]string{p.varName}, deps...), ", "))
+	}
+
+	params := []string{"lc goscade.Lifecycle"}
+	for _, in := range g.inputs {
+		params = append(params, fmt.Sprintf("%s %s", in.name, g.typeString(in.typ)))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by goscade gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg.Name)
+	g.renderImports(&src)
+	fmt.Fprintf(&src, "// %s constructs every goscade:component of this package and registers it\n", g.funcName)
+	fmt.Fprintf(&src, "// in lc with its dependencies declared explicitly, so the lifecycle can\n")
+	fmt.Fprintf(&src, "// run with goscade.WithoutReflection.\n")
+	fmt.Fprintf(&src, "func %s(%s) error {\n", g.funcName, strings.Join(params, ", "))
+	src.Write(body.Bytes())
+	fmt.Fprintf(&src, "\treturn nil\n}\n")
+
+	code, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return code, nil
+}
+
+// renderImports writes the import block, standard library packages first.
+func (g *generator) renderImports(src *bytes.Buffer) {
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	fmt.Fprintf(src, "import (\n")
+	for i, group := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 && len(other) > 0 {
+			fmt.Fprintf(src, "\n")
+		}
+		for _, path := range group {
+			if name := g.imports[path]; name != filepath.Base(path) && !(path == goscadePath && name == "goscade") {
+				fmt.Fprintf(src, "\t%s %q\n", name, path)
+			} else {
+				fmt.Fprintf(src, "\t%q\n", path)
+			}
+		}
+	}
+	fmt.Fprintf(src, ")\n\n")
+}
+
+// inputName returns the parameter name of the input with type t.
+func (g *generator) inputName(t types.Type) string {
+	for _, in := range g.inputs {
+		if types.Identical(in.typ, t) {
+			return in.name
+		}
+	}
+	panic("goscade gen: unknown input type " + t.String())
+}
+
+// typeString formats t relative to the generated package, recording imports.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == g.pkg.PkgPath {
+			return ""
+		}
+		if name, ok := g.imports[pkg.Path()]; ok {
+			return name
+		}
+
+		name := pkg.Name()
+		for taken := true; taken; {
+			taken = false
+			for _, existing := range g.imports {
+				if existing == name {
+					name += "_"
+					taken = true
+				}
+			}
+		}
+		g.imports[pkg.Path()] = name
+		return name
+	})
+}
+
+// typeBaseName returns the name of the named type behind t, if any.
+func typeBaseName(t types.Type) string {
+	for {
+		switch u := t.(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Named:
+			return u.Obj().Name()
+		default:
+			return "in"
+		}
+	}
+}
+
+// varName converts an exported identifier to a lowerCamel variable name.
+func varName(name string) string {
+	if name == "" {
+		return "comp"
+	}
+
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		// Keep the last upper-case letter of an acronym followed by lower case: HTTPServer -> httpServer.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	result := string(runes)
+	if token.IsKeyword(result) {
+		result += "_"
+	}
+	return result
+}
+
+// uniqueName returns name, or name with a numeric suffix, not present in used.
+func uniqueName(used map[string]bool, name string) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// appendUnique appends s to list unless it is already present.
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// packageClause returns the package name declared in src.
+func packageClause(src []byte) string {
+	for _, line := range strings.Split(string(src), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "package" {
+			return fields[1]
+		}
+	}
+	return "main"
+}
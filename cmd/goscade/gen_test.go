@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/cmd/goscade/testdata/app"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+func TestGenerate_MatchesCommittedOutput(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("testdata", "app", "goscade_gen.go"))
+	require.NoError(t, err)
+
+	got, err := generate(filepath.Join("testdata", "app"), "Wire", "goscade_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestGenerate_CommittedOutputCompiles(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir:  filepath.Join("testdata", "app"),
+	}, ".")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Empty(t, pkgs[0].Errors)
+}
+
+// TestGenerate_MatchesReflectiveGraph runs the committed wiring function and
+// checks that it declares the same graph reflection discovers for the same
+// components.
+func TestGenerate_MatchesReflectiveGraph(t *testing.T) {
+	generated := goscade.NewLifecycle(testutil.NopLogger{}, goscade.WithoutReflection())
+	require.NoError(t, app.Wire(generated, app.Config{}, http.NotFoundHandler()))
+
+	reflective := goscade.NewLifecycle(testutil.NopLogger{})
+	for comp := range generated.Dependencies() {
+		reflective.Register(comp)
+	}
+
+	diff := goscade.DiffGraphs(reflective.BuildGraph(), generated.BuildGraph())
+	assert.True(t, diff.IsEmpty(), "generated graph differs from the reflective one: %+v", diff)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := generate(filepath.Join("testdata", "ambiguous"), "Wire", "goscade_gen.go")
+	assert.ErrorContains(t, err, "NewC: parameter 0 of type goscade.Component is provided by several constructors: NewA, NewB")
+
+	_, err = generate(filepath.Join("testdata", "cycle"), "Wire", "goscade_gen.go")
+	assert.ErrorContains(t, err, "circular dependency: NewA -> NewB -> NewA")
+
+	_, err = generate(filepath.Join("testdata", "unprovided"), "Wire", "goscade_gen.go")
+	assert.ErrorContains(t, err, "NewService: parameter db of type *Database is a component that no //goscade:component constructor provides")
+}
+
+// TestGenerate_ExcludesOnlyTheOutputFile checks that a package file sharing
+// the output file's base name is still loaded.
+func TestGenerate_ExcludesOnlyTheOutputFile(t *testing.T) {
+	code, err := generate(filepath.Join("testdata", "app"), "Wire", filepath.Join("wiring", "app.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "func Wire(lc goscade.Lifecycle, config Config, handler http.Handler) error {")
+}
+
+func TestRun_Gen(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	output := filepath.Join(t.TempDir(), "wire.go")
+	_, _, err := runCommand("", "gen", "-o", output, "-func", "Setup", dir)
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "func Setup(lc goscade.Lifecycle, config Config, handler http.Handler) error {")
+}
+
+func TestVarName(t *testing.T) {
+	assert.Equal(t, "httpServer", varName("HTTPServer"))
+	assert.Equal(t, "db", varName("DB"))
+	assert.Equal(t, "cache", varName("Cache"))
+	assert.Equal(t, "type_", varName("Type"))
+	assert.Equal(t, "comp", varName(""))
+}
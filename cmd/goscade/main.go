@@ -0,0 +1,305 @@
+// Command goscade inspects dependency graphs exported by a goscade lifecycle
+// and generates reflection-free wiring code.
+//
+// Graphs are read from files written by WithGraphOutput (DOT or JSON) or
+// produced by Graph.ToDOT / Graph.ToJSON. Use "-" to read from stdin.
+//
+// Usage:
+//
+//	goscade render [-format dot|json|mermaid] <graph>
+//	goscade path <graph> <from> <to>
+//	goscade deps <graph> <node>
+//	goscade rdeps <graph> <node>
+//	goscade waves <graph>
+//	goscade cycles <graph>
+//	goscade diff <old-graph> <new-graph>
+//	goscade gen [-o file] [-func name] [dir]
+//
+// The gen command finds constructors annotated with a //goscade:component
+// directive and writes a function that calls them in dependency order and
+// registers every component with its dependencies passed to Register, so
+// that the lifecycle can be created with goscade.WithoutReflection.
+// Constructor parameters that no annotated constructor provides become
+// parameters of the generated function.
+//
+// The command must be built from source: its module replaces the goscade
+// library and analyzer with the copies in the same checkout, so it cannot be
+// installed with "go install ...@latest". From the cmd directory run:
+//
+//	go install ./goscade
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// errUsage is returned when the command line arguments are invalid.
+var errUsage = errors.New("invalid usage")
+
+// command describes a single goscade subcommand.
+type command struct {
+	usage string
+	run   func(env *env, args []string) error
+}
+
+// env holds the input and output streams used by subcommands.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var commands = map[string]command{
+	"render": {usage: "render [-format dot|json|mermaid] <graph>", run: runRender},
+	"path":   {usage: "path <graph> <from> <to>", run: runPath},
+	"deps":   {usage: "deps <graph> <node>", run: runDeps},
+	"rdeps":  {usage: "rdeps <graph> <node>", run: runRdeps},
+	"waves":  {usage: "waves <graph>", run: runWaves},
+	"cycles": {usage: "cycles <graph>", run: runCycles},
+	"diff":   {usage: "diff <old-graph> <new-graph>", run: runDiff},
+	"gen":    {usage: "gen [-o file] [-func name] [dir]", run: runGen},
+}
+
+func main() {
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	if err := run(e, os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(e.stderr, "goscade: %v\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run dispatches args to the matching subcommand.
+func run(e *env, args []string) error {
+	if len(args) == 0 {
+		printUsage(e.stderr)
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "goscade: unknown command %q\n", args[0])
+		printUsage(e.stderr)
+		return errUsage
+	}
+
+	err := cmd.run(e, args[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(e.stderr, "usage: goscade %s\n", cmd.usage)
+	}
+	return err
+}
+
+// printUsage writes the list of subcommands to w.
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: goscade <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+// readGraph loads a graph from path, or from stdin if path is "-".
+func (e *env) readGraph(path string) (goscade.Graph, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(e.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return goscade.Graph{}, err
+	}
+
+	graph, err := goscade.ParseGraph(data)
+	if err != nil {
+		return goscade.Graph{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return graph, nil
+}
+
+// readGraphNode loads a graph and checks that it contains every node in ids.
+func (e *env) readGraphNode(path string, ids ...string) (goscade.Graph, error) {
+	graph, err := e.readGraph(path)
+	if err != nil {
+		return goscade.Graph{}, err
+	}
+
+	for _, id := range ids {
+		if !graph.HasNode(id) {
+			return goscade.Graph{}, fmt.Errorf("node %q not found in %s", id, path)
+		}
+	}
+	return graph, nil
+}
+
+// printLines writes each line to stdout.
+func (e *env) printLines(lines []string) {
+	for _, line := range lines {
+		fmt.Fprintln(e.stdout, line)
+	}
+}
+
+func runRender(e *env, args []string) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	format := flags.String("format", "dot", "output format: dot, json or mermaid")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	graph, err := e.readGraph(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "dot":
+		fmt.Fprint(e.stdout, graph.ToDOT())
+	case "json":
+		data, err := graph.ToJSON()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, string(data))
+	case "mermaid":
+		fmt.Fprint(e.stdout, graph.ToMermaid())
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	return nil
+}
+
+func runPath(e *env, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+
+	graph, err := e.readGraphNode(args[0], args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	path := graph.Path(args[1], args[2])
+	if path == nil {
+		return fmt.Errorf("%q does not depend on %q", args[1], args[2])
+	}
+	fmt.Fprintln(e.stdout, strings.Join(path, " -> "))
+	return nil
+}
+
+func runDeps(e *env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	graph, err := e.readGraphNode(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	e.printLines(graph.Ancestors(args[1]))
+	return nil
+}
+
+func runRdeps(e *env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	graph, err := e.readGraphNode(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	e.printLines(graph.Descendants(args[1]))
+	return nil
+}
+
+func runWaves(e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	graph, err := e.readGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	for i, wave := range graph.Waves() {
+		fmt.Fprintf(e.stdout, "wave %d: %s\n", i, strings.Join(wave, ", "))
+	}
+	return nil
+}
+
+func runCycles(e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	graph, err := e.readGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	cycles := graph.Cycles()
+	for _, cycle := range cycles {
+		fmt.Fprintln(e.stdout, strings.Join(cycle, " <-> "))
+	}
+	if len(cycles) > 0 {
+		return fmt.Errorf("found %d dependency cycles", len(cycles))
+	}
+	return nil
+}
+
+func runDiff(e *env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	oldGraph, err := e.readGraph(args[0])
+	if err != nil {
+		return err
+	}
+	newGraph, err := e.readGraph(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff := goscade.DiffGraphs(oldGraph, newGraph)
+	for _, node := range diff.RemovedNodes {
+		fmt.Fprintf(e.stdout, "- node %s\n", node)
+	}
+	for _, node := range diff.AddedNodes {
+		fmt.Fprintf(e.stdout, "+ node %s\n", node)
+	}
+	for _, edge := range diff.RemovedEdges {
+		fmt.Fprintf(e.stdout, "- edge %s -> %s\n", edge.From, edge.To)
+	}
+	for _, edge := range diff.AddedEdges {
+		fmt.Fprintf(e.stdout, "+ edge %s -> %s\n", edge.From, edge.To)
+	}
+	if !diff.IsEmpty() {
+		return errors.New("graphs differ")
+	}
+	return nil
+}
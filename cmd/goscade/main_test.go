@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// testGraph is db <- cache <- api, db <- worker.
+var testGraph = goscade.Graph{
+	Nodes: []goscade.GraphNode{{ID: "db"}, {ID: "cache"}, {ID: "api"}, {ID: "worker"}},
+	Edges: []goscade.GraphEdge{
+		{From: "db", To: "cache"},
+		{From: "cache", To: "api"},
+		{From: "db", To: "worker"},
+	},
+}
+
+// writeGraph stores graph as a DOT file in a temporary directory.
+func writeGraph(t *testing.T, name string, graph goscade.Graph) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(graph.ToDOT()), 0o600))
+	return path
+}
+
+// runCommand executes the CLI and returns stdout, stderr and the error.
+func runCommand(stdin string, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(&env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRun_Usage(t *testing.T) {
+	_, stderr, err := runCommand("")
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, "usage: goscade <command>")
+
+	_, stderr, err = runCommand("", "unknown")
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	_, stderr, err = runCommand("", "deps")
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr, "usage: goscade deps <graph> <node>")
+}
+
+func TestRun_Render(t *testing.T) {
+	path := writeGraph(t, "graph.dot", testGraph)
+
+	stdout, _, err := runCommand("", "render", "-format", "json", path)
+	require.NoError(t, err)
+	graph, err := goscade.ParseGraph([]byte(stdout))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, testGraph.Edges, graph.Edges)
+
+	stdout, _, err = runCommand(testGraph.ToDOT(), "render", "-format", "mermaid", "-")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "flowchart TB")
+
+	_, _, err = runCommand("", "render", "-format", "png", path)
+	assert.EqualError(t, err, `unknown format "png"`)
+}
+
+func TestRun_Queries(t *testing.T) {
+	path := writeGraph(t, "graph.dot", testGraph)
+
+	stdout, _, err := runCommand("", "path", path, "api", "db")
+	require.NoError(t, err)
+	assert.Equal(t, "api -> cache -> db\n", stdout)
+
+	_, _, err = runCommand("", "path", path, "db", "api")
+	assert.EqualError(t, err, `"db" does not depend on "api"`)
+
+	stdout, _, err = runCommand("", "deps", path, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "cache\ndb\n", stdout)
+
+	stdout, _, err = runCommand("", "rdeps", path, "db")
+	require.NoError(t, err)
+	assert.Equal(t, "api\ncache\nworker\n", stdout)
+
+	_, _, err = runCommand("", "deps", path, "missing")
+	assert.ErrorContains(t, err, `node "missing" not found`)
+
+	stdout, _, err = runCommand("", "waves", path)
+	require.NoError(t, err)
+	assert.Equal(t, "wave 0: db\nwave 1: cache, worker\nwave 2: api\n", stdout)
+}
+
+func TestRun_Cycles(t *testing.T) {
+	stdout, _, err := runCommand("", "cycles", writeGraph(t, "graph.dot", testGraph))
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+
+	cyclic := testGraph
+	cyclic.Edges = append([]goscade.GraphEdge{{From: "api", To: "db"}}, testGraph.Edges...)
+	stdout, _, err = runCommand("", "cycles", writeGraph(t, "cyclic.dot", cyclic))
+	assert.EqualError(t, err, "found 1 dependency cycles")
+	assert.Equal(t, "api <-> cache <-> db\n", stdout)
+}
+
+func TestRun_Diff(t *testing.T) {
+	oldPath := writeGraph(t, "old.dot", testGraph)
+	_, _, err := runCommand("", "diff", oldPath, oldPath)
+	require.NoError(t, err)
+
+	changed := goscade.Graph{
+		Nodes: []goscade.GraphNode{{ID: "db"}, {ID: "api"}},
+		Edges: []goscade.GraphEdge{{From: "db", To: "api"}},
+	}
+	stdout, _, err := runCommand("", "diff", oldPath, writeGraph(t, "new.dot", changed))
+	assert.EqualError(t, err, "graphs differ")
+	assert.Equal(t, "- node cache\n- node worker\n"+
+		"- edge cache -> api\n- edge db -> cache\n- edge db -> worker\n"+
+		"+ edge db -> api\n", stdout)
+}
@@ -0,0 +1,29 @@
+// Package ambiguous is a fixture with two providers for one parameter.
+package ambiguous
+
+import (
+	"context"
+
+	"github.com/ognick/goscade/v2"
+)
+
+type A struct{}
+
+//goscade:component
+func NewA() *A { return &A{} }
+
+func (a *A) Run(context.Context, func(error)) error { return nil }
+
+type B struct{}
+
+//goscade:component
+func NewB() *B { return &B{} }
+
+func (b *B) Run(context.Context, func(error)) error { return nil }
+
+type C struct{}
+
+//goscade:component
+func NewC(dep goscade.Component) *C { return &C{} }
+
+func (c *C) Run(context.Context, func(error)) error { return nil }
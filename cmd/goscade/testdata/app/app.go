@@ -0,0 +1,67 @@
+// Package app is a fixture for "goscade gen" tests.
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ognick/goscade/v2"
+)
+
+type Config struct {
+	DSN string
+}
+
+type Storer interface {
+	goscade.Component
+	Store(key string) error
+}
+
+type Database struct {
+	cfg Config
+}
+
+//goscade:component
+func NewDatabase(cfg Config) (*Database, error) {
+	return &Database{cfg: cfg}, nil
+}
+
+func (d *Database) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+func (d *Database) Store(string) error { return nil }
+
+type HTTPServer struct {
+	store   Storer
+	handler http.Handler
+	cache   *Cache
+}
+
+//goscade:component
+func NewHTTPServer(store Storer, handler http.Handler, cache *Cache) *HTTPServer {
+	return &HTTPServer{store: store, handler: handler, cache: cache}
+}
+
+func (s *HTTPServer) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+type Cache struct {
+	db *Database
+}
+
+//goscade:component
+func NewCache(db *Database, cfg Config) *Cache {
+	return &Cache{db: db}
+}
+
+func (c *Cache) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
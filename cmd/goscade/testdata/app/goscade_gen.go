@@ -0,0 +1,28 @@
+// Code generated by goscade gen. DO NOT EDIT.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// Wire constructs every goscade:component of this package and registers it
+// in lc with its dependencies declared explicitly, so the lifecycle can
+// run with goscade.WithoutReflection.
+func Wire(lc goscade.Lifecycle, config Config, handler http.Handler) error {
+	database, err := NewDatabase(config)
+	if err != nil {
+		return err
+	}
+	lc.Register(database)
+
+	cache := NewCache(database, config)
+	lc.Register(cache, database)
+
+	httpServer := NewHTTPServer(database, handler, cache)
+	lc.Register(httpServer, database, cache)
+
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package cycle is a fixture with circular constructors.
+package cycle
+
+import (
+	"context"
+
+	_ "github.com/ognick/goscade/v2"
+)
+
+type A struct{}
+
+//goscade:component
+func NewA(b *B) *A { return &A{} }
+
+func (a *A) Run(context.Context, func(error)) error { return nil }
+
+type B struct{}
+
+//goscade:component
+func NewB(a *A) *B { return &B{} }
+
+func (b *B) Run(context.Context, func(error)) error { return nil }
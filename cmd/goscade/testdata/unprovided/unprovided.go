@@ -0,0 +1,24 @@
+// Package unprovided is a fixture with a component parameter that no
+// annotated constructor provides.
+package unprovided
+
+import (
+	"context"
+
+	"github.com/ognick/goscade/v2"
+)
+
+type Database struct{}
+
+func (d *Database) Run(context.Context, func(error)) error { return nil }
+
+var _ goscade.Component = (*Database)(nil)
+
+type Service struct {
+	db *Database
+}
+
+//goscade:component
+func NewService(db *Database) *Service { return &Service{db: db} }
+
+func (s *Service) Run(context.Context, func(error)) error { return nil }
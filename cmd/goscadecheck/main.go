@@ -0,0 +1,23 @@
+// Command goscadecheck reports likely goscade wiring mistakes.
+//
+// It can be run directly or as a go vet tool:
+//
+//	goscadecheck ./...
+//	go vet -vettool=$(which goscadecheck) ./...
+//
+// The command must be built from source: its module replaces the goscade
+// library and analyzer with the copies in the same checkout, so it cannot be
+// installed with "go install ...@latest". From the cmd directory run:
+//
+//	go install ./goscadecheck
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ognick/goscade/v2/analysis/goscadecheck"
+)
+
+func main() {
+	singlechecker.Main(goscadecheck.Analyzer)
+}
@@ -0,0 +1,114 @@
+package goscade
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type componentInfoKey struct{}
+
+type phaseKey struct{}
+
+type shutdownDeadlineKey struct{}
+
+// componentInfo is the metadata the lifecycle attaches to the contexts it
+// passes to a component.
+type componentInfo struct {
+	name        string
+	lifecycleID string
+	log         *slog.Logger
+}
+
+// componentContext returns a copy of parent carrying the metadata of the
+// component tracked by state.
+func (lc *lifecycle) componentContext(parent context.Context, state *componentState) context.Context {
+	return context.WithValue(parent, componentInfoKey{}, componentInfo{
+		name:        state.componentName,
+		lifecycleID: lc.id,
+		log:         state.log,
+	})
+}
+
+// withPhase returns a copy of parent carrying phase.
+func withPhase(parent context.Context, phase Phase) context.Context {
+	return context.WithValue(parent, phaseKey{}, phase)
+}
+
+// withShutdownDeadline returns a copy of parent whose ShutdownContext ends at
+// the time returned by deadline.
+func withShutdownDeadline(parent context.Context, deadline func() time.Time) context.Context {
+	return context.WithValue(parent, shutdownDeadlineKey{}, deadline)
+}
+
+// shutdownDeadline returns when the lifecycle stops waiting for gen to stop,
+// or the zero time if gen has not been asked to stop or has no budget.
+func (lc *lifecycle) shutdownDeadline(r *runState, state *componentState, gen *componentGeneration) time.Time {
+	at := gen.stopRequestedAt.Load()
+	if at == 0 {
+		return time.Time{}
+	}
+
+	var deadline time.Time
+	if state.shutdownTimeout > 0 {
+		deadline = time.Unix(0, at).Add(state.shutdownTimeout)
+	}
+	switch {
+	case r.drainedCtx.Err() != nil:
+		if deadline.IsZero() || r.shutdownDeadline.Before(deadline) {
+			deadline = r.shutdownDeadline
+		}
+	case r.lifecycleCtx.Err() == nil && deadline.IsZero() && lc.shutdownTimeout > 0:
+		deadline = time.Unix(0, at).Add(lc.shutdownTimeout)
+	}
+	return deadline
+}
+
+// ShutdownContext returns a context for stopping the component whose Run
+// context is ctx once ctx is done. It keeps the values of ctx but not its
+// cancellation, and its deadline is when the lifecycle stops waiting for the
+// component: the component's ShutdownTimeout or the lifecycle shutdown
+// timeout, whichever ends first. Without a known deadline the context only
+// ends when cancel is called.
+func ShutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	stopCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Value(shutdownDeadlineKey{}).(func() time.Time); ok {
+		if at := deadline(); !at.IsZero() {
+			return context.WithDeadline(stopCtx, at)
+		}
+	}
+	return context.WithCancel(stopCtx)
+}
+
+// ComponentName returns the name of the component that ctx was passed to by
+// the lifecycle, or "" if ctx does not come from a lifecycle.
+func ComponentName(ctx context.Context) string {
+	info, _ := ctx.Value(componentInfoKey{}).(componentInfo)
+	return info.name
+}
+
+// LifecycleID returns the ID of the lifecycle that passed ctx to a component,
+// or "" if ctx does not come from a lifecycle. The ID is logged with every
+// record under LogKeyLifecycle.
+func LifecycleID(ctx context.Context) string {
+	info, _ := ctx.Value(componentInfoKey{}).(componentInfo)
+	return info.lifecycleID
+}
+
+// Logger returns the logger of the component that ctx was passed to by the
+// lifecycle. Its records carry the lifecycle ID and the component name. If
+// ctx does not come from a lifecycle, Logger returns slog.Default().
+func Logger(ctx context.Context) *slog.Logger {
+	if info, ok := ctx.Value(componentInfoKey{}).(componentInfo); ok && info.log != nil {
+		return info.log
+	}
+	return slog.Default()
+}
+
+// CurrentPhase returns the Phase for which the lifecycle passed ctx to a
+// component: PhaseRun for Run, PhaseDrain for Drain and PhaseReload for
+// Reload. It returns "" if ctx does not come from a lifecycle.
+func CurrentPhase(ctx context.Context) Phase {
+	phase, _ := ctx.Value(phaseKey{}).(Phase)
+	return phase
+}
@@ -0,0 +1,132 @@
+package goscade
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+type contextMetadata struct {
+	name        string
+	lifecycleID string
+	phase       Phase
+	log         *slog.Logger
+}
+
+func readContextMetadata(ctx context.Context) contextMetadata {
+	return contextMetadata{
+		name:        ComponentName(ctx),
+		lifecycleID: LifecycleID(ctx),
+		phase:       CurrentPhase(ctx),
+		log:         Logger(ctx),
+	}
+}
+
+func TestContext_WithoutLifecycle(t *testing.T) {
+	metadata := readContextMetadata(context.Background())
+	assert.Empty(t, metadata.name)
+	assert.Empty(t, metadata.lifecycleID)
+	assert.Empty(t, metadata.phase)
+	assert.Same(t, slog.Default(), metadata.log)
+}
+
+func TestLifecycle_InjectsComponentMetadata(t *testing.T) {
+	var buf testutil.SyncBuffer
+	lc := NewLifecycle(nil, WithLogHandler(slog.NewJSONHandler(&buf, nil)))
+	run := make(chan contextMetadata, 2)
+	reload := make(chan contextMetadata, 1)
+	worker := &reloadingComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "worker", run: func(ctx context.Context, probe func(error)) error {
+			run <- readContextMetadata(ctx)
+			Logger(ctx).Info("hello from worker")
+			probe(nil)
+			<-ctx.Done()
+			return nil
+		}},
+		reload: func(ctx context.Context) error {
+			reload <- readContextMetadata(ctx)
+			return nil
+		},
+	}
+	lc.Register(worker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.NoError(t, lc.Reload(worker))
+	cancel()
+	receiveLifecycleError(t, done)
+
+	id := lc.(*lifecycle).id
+	runMetadata := <-run
+	assert.Equal(t, "worker", runMetadata.name)
+	assert.Equal(t, id, runMetadata.lifecycleID)
+	assert.Equal(t, PhaseRun, runMetadata.phase)
+	reloadMetadata := <-reload
+	assert.Equal(t, "worker", reloadMetadata.name)
+	assert.Equal(t, id, reloadMetadata.lifecycleID)
+	assert.Equal(t, PhaseReload, reloadMetadata.phase)
+
+	var hello map[string]any
+	for _, record := range jsonRecords(t, &buf) {
+		if record["msg"] == "hello from worker" {
+			hello = record
+		}
+	}
+	require.NotNil(t, hello)
+	assert.Equal(t, "worker", hello[LogKeyComponent])
+	assert.Equal(t, id, hello[LogKeyLifecycle])
+}
+
+type tenantKey struct{}
+
+func TestLifecycle_WithContextPropagation(t *testing.T) {
+	for _, propagate := range []bool{false, true} {
+		var opts []Option
+		if propagate {
+			opts = append(opts, WithContextPropagation())
+		}
+		lc := NewLifecycle(&mockLogger{}, opts...)
+		tenants := make(chan any, 1)
+		lc.Register(&lifecycleErrorComponent{name: "worker", run: func(ctx context.Context, probe func(error)) error {
+			tenants <- ctx.Value(tenantKey{})
+			probe(nil)
+			<-ctx.Done()
+			return nil
+		}})
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), tenantKey{}, "acme"))
+		ready, done := runLifecycleForErrors(lc, ctx)
+		require.NoError(t, receiveLifecycleError(t, ready))
+		cancel()
+		receiveLifecycleError(t, done)
+
+		if propagate {
+			assert.Equal(t, "acme", <-tenants)
+		} else {
+			assert.Nil(t, <-tenants)
+		}
+	}
+}
+
+func TestLifecycle_WithContextPropagationKeepsShutdownOrder(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{}, WithContextPropagation())
+	database := recordedComponent("database", events)
+	lc.Register(database)
+	lc.Register(recordedComponent("api", events), database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	receiveLifecycleError(t, done)
+
+	stopped := events.snapshot()[2:]
+	assert.Equal(t, []string{"api stopped", "database stopped"}, stopped)
+}
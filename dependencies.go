@@ -10,6 +10,9 @@ import (
 // to find all parent components it depends on. It uses reflection to examine fields,
 // slices, arrays, maps, and nested structures.
 //
+// If reflection is disabled (WithoutReflection), the fields of root are not
+// examined and only its implicit and linked dependencies are considered.
+//
 // Parameters:
 //   - root: Component to examine
 //
@@ -18,7 +21,13 @@ import (
 func (lc *lifecycle) findParentComponents(root Component) map[Component]struct{} {
 	visited := make(map[uintptr]struct{})
 	queue := fifoQueue[reflect.Value]{}
-	queue.Push(reflect.ValueOf(root))
+	if !lc.disableReflection {
+		queue.Push(reflect.ValueOf(root))
+	} else {
+		// Mark root as visited so linked structs pointing back to it
+		// do not make it its own parent.
+		visited[reflect.ValueOf(root).Pointer()] = struct{}{}
+	}
 	parents := make(map[Component]struct{})
 	for dep := range lc.compToImplicitDeps[root] {
 		parents[dep] = struct{}{}
@@ -31,7 +40,8 @@ func (lc *lifecycle) findParentComponents(root Component) map[Component]struct{}
 		queue.Push(reflect.ValueOf(dep))
 	}
 
-	var initialized bool
+	// Without reflection root is never pushed, so every pointer popped is a dep.
+	initialized := lc.disableReflection
 	for !queue.IsEmpty() {
 		val, _ := queue.Pop()
 		if val.Kind() == reflect.Interface {
@@ -127,9 +137,22 @@ func findCircularDependencies(
 //
 // Components without dependencies will have an empty slice.
 // This method is useful for debugging and understanding the component graph.
+//
+// While Run is active, it returns the graph built when Run started:
+// components are not inspected again while they run.
 func (lc *lifecycle) Dependencies() map[Component][]Component {
+	lc.mu.RLock()
+	r := lc.run
+	lc.mu.RUnlock()
+	if r == nil {
+		return lc.dependencies(lc.buildCompToParents())
+	}
+	return lc.dependencies(r.compToParents)
+}
+
+// dependencies converts compToParents into the map returned by Dependencies.
+func (lc *lifecycle) dependencies(compToParents map[Component]map[Component]struct{}) map[Component][]Component {
 	deps := make(map[Component][]Component)
-	compToParents := lc.buildCompToParents()
 	for comp := range lc.components {
 		parents, ok := compToParents[comp]
 		if !ok {
@@ -541,3 +541,51 @@ func TestBuildCompToParents_CycleGraph(t *testing.T) {
 	rec1.Dep = rec2 // Create cycle
 	assert.Panicsf(t, func() { lc.Dependencies() }, "Expected panic due to cycle in dependencies")
 }
+
+// TestWithoutReflection_IgnoresFields verifies that field references are not
+// discovered when reflection is disabled, while explicit deps still are.
+func TestWithoutReflection_IgnoresFields(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{}, WithoutReflection()).(*lifecycle)
+
+	dep := &mockComponent{name: "dep"}
+	linked := &mockComponent{name: "linked"}
+	comp := &TestStruct{Dep1: dep}
+	lc.Register(dep)
+	lc.Register(linked)
+	lc.Link(comp, &struct{ Linked *mockComponent }{Linked: linked}, comp)
+
+	parents := lc.findParentComponents(comp)
+	if len(parents) != 1 {
+		t.Fatalf("expected only the linked parent, got %d parents", len(parents))
+	}
+	if _, ok := parents[linked]; !ok {
+		t.Errorf("expected linked component to be a parent")
+	}
+}
+
+// TestWithoutReflection_MatchesReflectiveGraph verifies that declaring every
+// dependency explicitly reproduces the graph discovered by reflection.
+func TestWithoutReflection_MatchesReflectiveGraph(t *testing.T) {
+	a := &componentA{}
+	b := &componentB{a: a}
+	c := &componentC{b: b}
+
+	reflective := NewLifecycle(&mockLogger{})
+	reflective.Register(a)
+	reflective.Register(b)
+	reflective.Register(c)
+
+	explicit := NewLifecycle(&mockLogger{}, WithoutReflection())
+	explicit.Register(a)
+	explicit.Register(b, a)
+	explicit.Register(c, b)
+
+	if len(explicit.BuildGraph().Edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(explicit.BuildGraph().Edges))
+	}
+
+	diff := DiffGraphs(reflective.BuildGraph(), explicit.BuildGraph())
+	if !diff.IsEmpty() {
+		t.Errorf("expected identical graphs, got diff %+v", diff)
+	}
+}
@@ -0,0 +1,83 @@
+package goscade
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Drainer can be implemented by a component that needs to finish in-flight
+// work before the components it depends on are stopped, such as an HTTP server
+// or a queue consumer.
+//
+// When shutdown is requested, the lifecycle switches to
+// LifecycleStatusDraining, waits for the pre-drain delay (see
+// WithPreDrainDelay) and then calls Drain concurrently on every ready Drainer
+// in the graph. The cascade shutdown starts once all of them have returned or
+// the drain timeout (see WithDrainTimeout) has expired. The context passed to
+// Drain is done when the drain timeout expires. Errors returned by Drain are
+// reported as a *ComponentError in PhaseDrain and do not prevent shutdown.
+type Drainer interface {
+	// Drain stops accepting new work and waits for in-flight work to complete.
+	Drain(ctx context.Context) error
+}
+
+// drain runs the drain phase of the shutdown. It returns immediately if no
+// ready component implements Drainer and no pre-drain delay is configured.
+func (lc *lifecycle) drain(r *runState) {
+	drainers := make(map[Component]Drainer)
+	for comp, state := range r.compStates {
+		gen := state.current()
+		if drainer, ok := asOptional[Drainer](comp); ok && gen.ready() && gen.teardownCtx.Err() == nil {
+			drainers[comp] = drainer
+		}
+	}
+	if len(drainers) == 0 && lc.preDrainDelay <= 0 {
+		return
+	}
+	if !lc.setStatus(LifecycleStatusDraining) {
+		return
+	}
+
+	if lc.preDrainDelay > 0 {
+		lc.log.Info("waiting before draining", LogKeyDuration, lc.preDrainDelay)
+		time.Sleep(lc.preDrainDelay)
+	}
+
+	var wg sync.WaitGroup
+	for comp, drainer := range drainers {
+		wg.Add(1)
+		go func(comp Component, drainer Drainer) {
+			defer wg.Done()
+			state := r.compStates[comp]
+			state.compareAndSetStatus(ComponentStatusReady, ComponentStatusDraining)
+			if err := lc.drainComponent(state, drainer); err != nil {
+				r.componentErrs.add(state.newError(comp, PhaseDrain, err))
+				state.log.Error("component drain failed", LogKeyPhase, PhaseDrain, LogKeyError, err)
+				return
+			}
+			state.log.Info("component drained")
+		}(comp, drainer)
+	}
+	wg.Wait()
+}
+
+// drainComponent calls Drain and waits for it to return, or gives up when
+// the drain timeout expires.
+func (lc *lifecycle) drainComponent(state *componentState, drainer Drainer) error {
+	ctx, cancel := context.WithTimeout(state.current().traceCtx, lc.drainTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go runPhase(ctx, state.componentName, PhaseDrain, func(ctx context.Context) {
+		done <- drainer.Drain(ctx)
+	})
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("not drained after %s: %w", lc.drainTimeout, ctx.Err())
+	}
+}
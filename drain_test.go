@@ -0,0 +1,149 @@
+package goscade
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type drainingComponent struct {
+	lifecycleErrorComponent
+	drain func(context.Context) error
+}
+
+func (c *drainingComponent) Drain(ctx context.Context) error {
+	return c.drain(ctx)
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) record(event string) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *eventRecorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func TestLifecycle_DrainsBeforeCascade(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{}, WithPreDrainDelay(20*time.Millisecond))
+	database := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		events.record("database stopped")
+		return nil
+	}}
+	api := &drainingComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "api", run: func(ctx context.Context, probe func(error)) error {
+			probe(nil)
+			<-ctx.Done()
+			events.record("api stopped")
+			return nil
+		}},
+		drain: func(ctx context.Context) error {
+			assert.Equal(t, LifecycleStatusDraining, lc.Status())
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			events.record("api drained")
+			return nil
+		},
+	}
+	lc.Register(database)
+	lc.Register(api, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return lc.Status() == LifecycleStatusDraining
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, events.snapshot())
+
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.Equal(t, []string{"api drained", "api stopped", "database stopped"}, events.snapshot())
+}
+
+func TestLifecycle_DrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	lc := NewLifecycle(&mockLogger{}, WithDrainTimeout(20*time.Millisecond))
+	lc.Register(&drainingComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "consumer", run: func(ctx context.Context, probe func(error)) error {
+			probe(nil)
+			<-ctx.Done()
+			return nil
+		}},
+		drain: func(context.Context) error {
+			<-release
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, context.DeadlineExceeded)
+	compErrs := ComponentErrors(runErr)
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseDrain, compErrs[0].Phase)
+	assert.EqualError(t, compErrs[0], "component consumer drain: not drained after 20ms: context deadline exceeded")
+	assert.Equal(t, LifecycleStatusStopped, lc.Status())
+}
+
+func TestAdapter_DrainsDelegate(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(NewAdapter(&drainingComponent{drain: func(context.Context) error {
+		events.record("drained")
+		return nil
+	}}, func(ctx context.Context, _ *drainingComponent, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.Equal(t, []string{"drained"}, events.snapshot())
+}
+
+func TestAdapter_WithoutDrainerSkipsDraining(t *testing.T) {
+	var statuses []LifecycleStatus
+	lc := NewLifecycle(&mockLogger{}, WithEventHandler(func(event Event) {
+		if event.LifecycleStatus != "" {
+			statuses = append(statuses, event.LifecycleStatus)
+		}
+	}))
+	lc.Register(FromCloser(io.NopCloser(nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.NotContains(t, statuses, LifecycleStatusDraining)
+	assert.Equal(t, LifecycleStatusStopped, statuses[len(statuses)-1])
+}
@@ -0,0 +1,261 @@
+package goscade
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Phase identifies the stage of a component's lifecycle in which an error occurred.
+type Phase string
+
+const (
+	// PhaseWaiting indicates the component failed while waiting for its
+	// dependencies to become ready.
+	PhaseWaiting Phase = "waiting"
+
+	// PhaseStart indicates the component did not become ready before the
+	// start timeout.
+	PhaseStart Phase = "start"
+
+	// PhaseReadiness indicates the component reported an error through its
+	// readiness probe.
+	PhaseReadiness Phase = "readiness"
+
+	// PhaseRun indicates the component's Run returned or failed while the
+	// component was running.
+	PhaseRun Phase = "run"
+
+	// PhaseDrain indicates the component failed to drain in-flight work
+	// before the cascade shutdown. See Drainer.
+	PhaseDrain Phase = "drain"
+
+	// PhaseReload indicates the component failed to apply new configuration.
+	// See Reloader.
+	PhaseReload Phase = "reload"
+
+	// PhaseShutdown indicates the component failed while it was being stopped.
+	PhaseShutdown Phase = "shutdown"
+)
+
+// ComponentError describes a failure of a single component. Errors returned
+// by Lifecycle.Run wrap a *ComponentError for every failed component; use
+// errors.As to inspect the first one or ComponentErrors to list all of them.
+type ComponentError struct {
+	// Component is the component that failed.
+	Component Component
+	// Name is the display name of the component.
+	Name string
+	// Phase is the lifecycle phase in which the failure occurred.
+	Phase Phase
+	// Time is the moment the failure was observed.
+	Time time.Time
+	// Err is the underlying error.
+	Err error
+}
+
+// newComponentError creates a ComponentError observed now.
+func newComponentError(comp Component, name string, phase Phase, err error) *ComponentError {
+	return &ComponentError{
+		Component: comp,
+		Name:      name,
+		Phase:     phase,
+		Time:      time.Now(),
+		Err:       err,
+	}
+}
+
+// Error returns the component name, the phase (omitted for PhaseRun) and the
+// underlying error.
+func (e *ComponentError) Error() string {
+	if e.Phase == PhaseRun {
+		return fmt.Sprintf("component %s: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("component %s %s: %v", e.Name, e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// ComponentErrors returns every *ComponentError contained in err, including
+// those joined with errors.Join, in the order they appear. Errors wrapped
+// inside a ComponentError are not inspected further.
+func ComponentErrors(err error) []*ComponentError {
+	var result []*ComponentError
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case *ComponentError:
+			result = append(result, e)
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				walk(child)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+
+	walk(err)
+	return result
+}
+
+// StuckComponent describes a component that had not stopped when the
+// lifecycle shutdown timeout fired.
+type StuckComponent struct {
+	// Component is the component that had not stopped.
+	Component Component
+	// Name is the display name of the component.
+	Name string
+	// Stopping is how long ago the component was asked to stop. It is zero if
+	// the component was still waiting for its dependents to stop.
+	Stopping time.Duration
+	// Goroutines is the goroutine profile of the goroutines labeled with the
+	// component name. It is only collected with WithGoroutineDump.
+	Goroutines string
+}
+
+// StuckComponentsError is returned by Lifecycle.Run when the shutdown timeout
+// fires. It lists every component that had not stopped yet, sorted by name,
+// and matches ShutdownTimeoutError with errors.Is.
+type StuckComponentsError struct {
+	// Timeout is the shutdown timeout that elapsed.
+	Timeout time.Duration
+	// Components are the components that had not stopped.
+	Components []StuckComponent
+}
+
+// Error lists the stuck components and how long each has been stopping.
+func (e *StuckComponentsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v after %s", ShutdownTimeoutError, e.Timeout)
+	for i, comp := range e.Components {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		if comp.Stopping > 0 {
+			fmt.Fprintf(&b, "%s stopping for %s", comp.Name, comp.Stopping.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(&b, "%s waiting for dependents", comp.Name)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns ShutdownTimeoutError.
+func (e *StuckComponentsError) Unwrap() error {
+	return ShutdownTimeoutError
+}
+
+// GoroutineLeak describes goroutines started by a component that were still
+// running after the component had stopped.
+type GoroutineLeak struct {
+	// Component is the component that started the goroutines.
+	Component Component
+	// Name is the display name of the component.
+	Name string
+	// Count is the number of leaked goroutines.
+	Count int
+	// Goroutines is the goroutine profile of the leaked goroutines.
+	Goroutines string
+}
+
+// LeakedGoroutinesError is returned by Lifecycle.Run with
+// WithLeakCheck(LeakCheckError) when components leak goroutines. It lists the
+// leaks sorted by component name and matches GoroutineLeakError with errors.Is.
+type LeakedGoroutinesError struct {
+	// Leaks are the leaked goroutines grouped by component.
+	Leaks []GoroutineLeak
+}
+
+// Error lists the components and the number of goroutines each leaked.
+func (e *LeakedGoroutinesError) Error() string {
+	var b strings.Builder
+	b.WriteString(GoroutineLeakError.Error())
+	for i, leak := range e.Leaks {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s leaked %d goroutines", leak.Name, leak.Count)
+	}
+	return b.String()
+}
+
+// Unwrap returns GoroutineLeakError.
+func (e *LeakedGoroutinesError) Unwrap() error {
+	return GoroutineLeakError
+}
+
+// PanicError is returned when a component's Run method panics.
+// The lifecycle recovers the panic, treats it as a component failure and
+// initiates a cascade shutdown of the remaining components.
+type PanicError struct {
+	// Component is the component whose Run method panicked.
+	Component Component
+	// Name is the display name of the component.
+	Name string
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error returns the panic value formatted as an error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is and
+// errors.As can match errors passed to panic.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recover calls fn and converts a panic into a *PanicError for comp, named
+// after ComponentName(ctx). Components that run work outside their Run
+// goroutine, such as job or worker functions, use it to report panics the way
+// the lifecycle reports a panicking Run.
+func Recover(ctx context.Context, comp Component, fn func() error) error {
+	return recoverPanic(comp, ComponentName(ctx), fn)
+}
+
+// runRecovered calls comp.Run and converts a panic into a *PanicError.
+func runRecovered(
+	ctx context.Context,
+	comp Component,
+	name string,
+	readinessProbe func(cause error),
+) error {
+	return recoverPanic(comp, name, func() (err error) {
+		runPhase(ctx, name, PhaseRun, func(ctx context.Context) {
+			err = comp.Run(ctx, readinessProbe)
+		})
+		return err
+	})
+}
+
+// recoverPanic calls fn and converts a panic into a *PanicError.
+func recoverPanic(comp Component, name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Component: comp,
+				Name:      name,
+				Value:     r,
+				Stack:     debug.Stack(),
+			}
+		}
+	}()
+	return fn()
+}
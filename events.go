@@ -0,0 +1,144 @@
+package goscade
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// eventBufferSize is the number of events buffered for each subscriber.
+const eventBufferSize = 64
+
+// Event describes a change of the lifecycle status or of a component status,
+// or an error reported by a component.
+type Event struct {
+	// Time is the moment the status changed.
+	Time time.Time `json:"time"`
+	// Component is the name of the component whose status changed. It is
+	// empty if the lifecycle status changed.
+	Component string `json:"component,omitempty"`
+	// ComponentStatus is the new status of Component.
+	ComponentStatus ComponentStatus `json:"component_status,omitempty"`
+	// LifecycleStatus is the new status of the lifecycle. It is empty if a
+	// component status changed.
+	LifecycleStatus LifecycleStatus `json:"lifecycle_status,omitempty"`
+	// Err is the error reported by Component. Events carrying an error do not
+	// change any status.
+	Err *ComponentError `json:"-"`
+}
+
+// eventBus fans events out to subscribers. Events are dropped for subscribers
+// whose buffer is full, so a slow subscriber never blocks the lifecycle.
+type eventBus struct {
+	handlers []func(Event)
+
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// subscribe returns a channel that receives events until ctx is done.
+func (b *eventBus) subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, eventBufferSize)
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan Event]struct{})
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		close(ch)
+	})
+	return ch
+}
+
+// publish calls every handler and delivers event to every subscriber that
+// has room for it.
+func (b *eventBus) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives every lifecycle and component
+// status change until ctx is done, after which the channel is closed.
+// Events are dropped if the subscriber does not keep up with them.
+func (lc *lifecycle) Subscribe(ctx context.Context) <-chan Event {
+	return lc.events.subscribe(ctx)
+}
+
+// ComponentSnapshot describes a registered component.
+type ComponentSnapshot struct {
+	// Component is the registered component.
+	Component Component `json:"-"`
+	// Name is the display name of the component.
+	Name string `json:"name"`
+	// Status is the status of the component. It is empty before Run.
+	Status ComponentStatus `json:"status,omitempty"`
+	// Dependencies are the names of the components it depends on, sorted.
+	Dependencies []string `json:"dependencies"`
+}
+
+// Snapshot describes the state of the lifecycle at a point in time.
+type Snapshot struct {
+	// Status is the status of the lifecycle.
+	Status LifecycleStatus `json:"status"`
+	// Components are the registered components sorted by name.
+	Components []ComponentSnapshot `json:"components"`
+}
+
+// Snapshot returns the status of the lifecycle and of every registered
+// component.
+func (lc *lifecycle) Snapshot() Snapshot {
+	lc.mu.RLock()
+	r, status := lc.run, lc.status
+	lc.mu.RUnlock()
+	if r == nil {
+		return lc.snapshot(status, lc.dependencies(lc.buildCompToParents()), nil)
+	}
+	return lc.snapshot(status, lc.dependencies(r.compToParents), r.compStates)
+}
+
+// snapshot describes the lifecycle with the given status, dependency graph
+// and component states.
+func (lc *lifecycle) snapshot(
+	status LifecycleStatus,
+	dependencies map[Component][]Component,
+	compStates map[Component]*componentState,
+) Snapshot {
+	snapshot := Snapshot{
+		Status:     status,
+		Components: make([]ComponentSnapshot, 0, len(dependencies)),
+	}
+	for comp, parents := range dependencies {
+		compSnapshot := ComponentSnapshot{
+			Component:    comp,
+			Name:         lc.componentName(comp),
+			Dependencies: make([]string, 0, len(parents)),
+		}
+		if state, ok := compStates[comp]; ok {
+			compSnapshot.Status = state.getStatus()
+		}
+		for _, parent := range parents {
+			compSnapshot.Dependencies = append(compSnapshot.Dependencies, lc.componentName(parent))
+		}
+		sort.Strings(compSnapshot.Dependencies)
+		snapshot.Components = append(snapshot.Components, compSnapshot)
+	}
+	sort.Slice(snapshot.Components, func(i, j int) bool {
+		return snapshot.Components[i].Name < snapshot.Components[j].Name
+	})
+	return snapshot
+}
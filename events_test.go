@@ -0,0 +1,199 @@
+package goscade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle_SubscribeReceivesStatusChanges(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(recordedComponent("database", &eventRecorder{}))
+
+	subCtx, unsubscribe := context.WithCancel(context.Background())
+	events := lc.Subscribe(subCtx)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	receiveLifecycleError(t, done)
+	unsubscribe()
+
+	var lifecycleStatuses []LifecycleStatus
+	var componentStatuses []ComponentStatus
+	for event := range events {
+		assert.False(t, event.Time.IsZero())
+		if event.Component == "" {
+			lifecycleStatuses = append(lifecycleStatuses, event.LifecycleStatus)
+			continue
+		}
+		assert.Equal(t, "database", event.Component)
+		componentStatuses = append(componentStatuses, event.ComponentStatus)
+	}
+	assert.Equal(t, []LifecycleStatus{
+		LifecycleStatusRunning,
+		LifecycleStatusReady,
+		LifecycleStatusStopping,
+		LifecycleStatusStopped,
+	}, lifecycleStatuses)
+	assert.Equal(t, []ComponentStatus{
+		ComponentStatusWaiting,
+		ComponentStatusStarting,
+		ComponentStatusReady,
+		ComponentStatusStopping,
+		ComponentStatusStopped,
+	}, componentStatuses)
+}
+
+func TestEventBus_DropsEventsForSlowSubscribers(t *testing.T) {
+	bus := &eventBus{}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := bus.subscribe(ctx)
+	for i := 0; i < eventBufferSize+1; i++ {
+		bus.publish(Event{Component: "database"})
+	}
+	cancel()
+
+	count := 0
+	for range events {
+		count++
+	}
+	assert.Equal(t, eventBufferSize, count)
+}
+
+func TestLifecycle_Snapshot(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	database := recordedComponent("database", &eventRecorder{})
+	api := recordedComponent("api", &eventRecorder{})
+	lc.Register(database)
+	lc.Register(api, database)
+
+	snapshot := lc.Snapshot()
+	assert.Equal(t, LifecycleStatusIdle, snapshot.Status)
+	require.Len(t, snapshot.Components, 2)
+	assert.Equal(t, ComponentSnapshot{Component: api, Name: "api", Dependencies: []string{"database"}}, snapshot.Components[0])
+	assert.Equal(t, ComponentSnapshot{Component: database, Name: "database", Dependencies: []string{}}, snapshot.Components[1])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.NoError(t, lc.Stop(api))
+
+	snapshot = lc.Snapshot()
+	assert.Equal(t, LifecycleStatusReady, snapshot.Status)
+	assert.Equal(t, ComponentStatusStopped, snapshot.Components[0].Status)
+	assert.Equal(t, ComponentStatusReady, snapshot.Components[1].Status)
+
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+// mutatingComponent appends to its own fields until it is stopped.
+type mutatingComponent struct {
+	database *lifecycleErrorComponent
+	history  []int
+}
+
+func (c *mutatingComponent) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	for i := 0; ctx.Err() == nil; i++ {
+		c.history = append(c.history[:0], i)
+		time.Sleep(10 * time.Microsecond)
+	}
+	return nil
+}
+
+func TestLifecycle_SnapshotWhileComponentsMutateFields(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	database := recordedComponent("database", &eventRecorder{})
+	comp := &mutatingComponent{database: database}
+	lc.Register(comp)
+	lc.Register(database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, []Component{database}, lc.Dependencies()[comp])
+		assert.Len(t, lc.Snapshot().Components, 2)
+		assert.Len(t, lc.BuildGraph().Edges, 1)
+	}
+
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(recordedComponent("database", &eventRecorder{}))
+	assert.ErrorIs(t, lc.Shutdown(), LifecycleNotReadyError)
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.NoError(t, lc.Shutdown())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, ComponentErrors(err))
+	case <-time.After(time.Second):
+		t.Fatal("lifecycle did not stop")
+	}
+	assert.Equal(t, LifecycleStatusStopped, lc.Status())
+}
+
+func TestLifecycle_AfterRunReturns(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	database := recordedComponent("database", &eventRecorder{})
+	lc.Register(database)
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.NoError(t, lc.Shutdown())
+	receiveLifecycleError(t, done)
+
+	assert.ErrorIs(t, lc.Shutdown(), LifecycleNotReadyError)
+
+	// The graph reflects components registered after Run has returned
+	cache := recordedComponent("cache", &eventRecorder{})
+	lc.Register(cache, database)
+	assert.Equal(t, []Component{database}, lc.Dependencies()[cache])
+	assert.Len(t, lc.Snapshot().Components, 2)
+	assert.Len(t, lc.BuildGraph().Edges, 1)
+}
+
+func TestLifecycle_WithEventHandlerReceivesErrors(t *testing.T) {
+	runErr := errors.New("connection lost")
+	var events []Event
+	lc := NewLifecycle(&mockLogger{}, WithEventHandler(func(event Event) {
+		events = append(events, event)
+	}))
+	lc.Register(&lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		return runErr
+	}})
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	receiveLifecycleError(t, done)
+
+	var errEvents []Event
+	for _, event := range events {
+		if event.Err != nil {
+			errEvents = append(errEvents, event)
+		}
+	}
+	require.Len(t, errEvents, 1)
+	assert.Equal(t, "database", errEvents[0].Component)
+	assert.Empty(t, errEvents[0].ComponentStatus)
+	assert.Equal(t, PhaseRun, errEvents[0].Err.Phase)
+	assert.ErrorIs(t, errEvents[0].Err, runErr)
+	assert.Equal(t, LifecycleStatusStopped, events[len(events)-1].LifecycleStatus)
+}
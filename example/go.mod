@@ -3,13 +3,11 @@ module github.com/ognick/goscade/example
 go 1.22
 
 require (
-	github.com/ognick/goscade v0.0.0
+	github.com/ognick/goscade/v2 v2.0.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.6.0
 )
 
-require (
-	go.uber.org/multierr v1.10.0 // indirect
-	golang.org/x/sync v0.6.0 // indirect
-)
+require go.uber.org/multierr v1.10.0 // indirect
 
-replace github.com/ognick/goscade => ../
+replace github.com/ognick/goscade/v2 => ../
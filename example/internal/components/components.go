@@ -3,7 +3,7 @@ package components
 import (
 	"context"
 
-	"github.com/ognick/goscade"
+	"github.com/ognick/goscade/v2"
 )
 
 type RedisClient struct {
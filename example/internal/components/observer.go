@@ -9,7 +9,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ognick/goscade"
+	"github.com/ognick/goscade/v2"
 )
 
 type Status string
@@ -9,9 +9,9 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ognick/goscade"
 	"github.com/ognick/goscade/example/internal/components"
 	"github.com/ognick/goscade/example/internal/domain"
+	"github.com/ognick/goscade/v2"
 	"golang.org/x/sync/errgroup"
 )
 
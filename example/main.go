@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 
-	"github.com/ognick/goscade"
 	"github.com/ognick/goscade/example/internal/api"
 	"github.com/ognick/goscade/example/internal/usecase"
 	"github.com/ognick/goscade/example/pkg"
+	"github.com/ognick/goscade/v2"
 )
 
 const addr = "127.0.0.1:8080"
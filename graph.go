@@ -1,8 +1,14 @@
 package goscade
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // GraphNode represents a node in the dependency graph.
@@ -26,8 +32,11 @@ type Graph struct {
 // BuildGraph constructs a visual graph representation based on component dependencies.
 // Returns a Graph structure containing all nodes (components) and edges (dependencies).
 func (lc *lifecycle) BuildGraph() Graph {
-	dependencies := lc.Dependencies()
+	return lc.buildGraph(lc.Dependencies())
+}
 
+// buildGraph converts dependencies into a Graph.
+func (lc *lifecycle) buildGraph(dependencies map[Component][]Component) Graph {
 	graph := Graph{
 		Nodes: make([]GraphNode, 0, len(dependencies)),
 		Edges: make([]GraphEdge, 0),
@@ -82,14 +91,21 @@ func (g Graph) ToDOT() string {
 	return result
 }
 
-// writeGraphToFile writes the dependency graph to a file in DOT format.
+// writeGraphToFile writes the dependency graph to a file in DOT format,
+// or in JSON format if the file name has a .json extension.
 func (lc *lifecycle) writeGraphToFile() error {
 	if lc.graphOutputFile == "" {
 		return nil
 	}
 
 	graph := lc.BuildGraph()
-	dotContent := graph.ToDOT()
+	content := []byte(graph.ToDOT())
+	if strings.EqualFold(filepath.Ext(lc.graphOutputFile), ".json") {
+		var err error
+		if content, err = graph.ToJSON(); err != nil {
+			return fmt.Errorf("failed to encode graph: %w", err)
+		}
+	}
 
 	file, err := os.Create(lc.graphOutputFile)
 	if err != nil {
@@ -97,11 +113,408 @@ func (lc *lifecycle) writeGraphToFile() error {
 	}
 	defer file.Close()
 
-	// coverage: ignore - WriteString error is extremely rare (disk full, I/O error)
+	// coverage: ignore - Write error is extremely rare (disk full, I/O error)
 	// and cannot be reliably tested without system-level mocks
-	if _, err := file.WriteString(dotContent); err != nil {
+	if _, err := file.Write(content); err != nil {
 		return fmt.Errorf("failed to write graph: %w", err)
 	}
 
 	return nil
 }
+
+// ToJSON converts the graph to indented JSON.
+// The output can be read back with ParseGraph.
+func (g Graph) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// ToMermaid converts the graph to a Mermaid flowchart definition.
+func (g Graph) ToMermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	mermaidID := func(node string) string {
+		if id, ok := ids[node]; ok {
+			return id
+		}
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[node] = id
+		return id
+	}
+
+	var result string
+	result += "flowchart TB\n"
+	for _, node := range g.Nodes {
+		result += fmt.Sprintf("  %s[%q]\n", mermaidID(node.ID), node.ID)
+	}
+
+	for _, edge := range g.Edges {
+		if edge.Label != "" {
+			result += fmt.Sprintf("  %s -->|%q| %s\n", mermaidID(edge.From), edge.Label, mermaidID(edge.To))
+		} else {
+			result += fmt.Sprintf("  %s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+		}
+	}
+
+	return result
+}
+
+// ParseGraph reads a graph previously exported as JSON (Graph.ToJSON) or
+// DOT (Graph.ToDOT, WithGraphOutput). The format is detected from the content.
+func ParseGraph(data []byte) (Graph, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var g Graph
+		if err := json.Unmarshal([]byte(trimmed), &g); err != nil {
+			return Graph{}, fmt.Errorf("failed to parse graph json: %w", err)
+		}
+		return g, nil
+	}
+
+	return parseDOT(trimmed)
+}
+
+// parseDOT parses the subset of DOT produced by Graph.ToDOT: quoted node
+// statements and quoted edge statements with an optional label attribute.
+func parseDOT(data string) (Graph, error) {
+	if !strings.HasPrefix(data, "digraph") {
+		return Graph{}, errors.New("failed to parse graph: expected JSON object or DOT digraph")
+	}
+
+	g := Graph{
+		Nodes: make([]GraphNode, 0),
+		Edges: make([]GraphEdge, 0),
+	}
+	seen := make(map[string]struct{})
+	addNode := func(id string) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			g.Nodes = append(g.Nodes, GraphNode{ID: id})
+		}
+	}
+
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, `"`) {
+			continue
+		}
+
+		from, rest, err := readQuoted(line)
+		if err != nil {
+			return Graph{}, fmt.Errorf("failed to parse graph line %d: %w", i+1, err)
+		}
+		addNode(from)
+
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, "->") {
+			continue
+		}
+
+		to, rest, err := readQuoted(strings.TrimSpace(strings.TrimPrefix(rest, "->")))
+		if err != nil {
+			return Graph{}, fmt.Errorf("failed to parse graph line %d: %w", i+1, err)
+		}
+		addNode(to)
+
+		edge := GraphEdge{From: from, To: to}
+		if idx := strings.Index(rest, "label="); idx >= 0 {
+			if edge.Label, _, err = readQuoted(rest[idx+len("label="):]); err != nil {
+				return Graph{}, fmt.Errorf("failed to parse graph line %d: %w", i+1, err)
+			}
+		}
+		g.Edges = append(g.Edges, edge)
+	}
+
+	return g, nil
+}
+
+// readQuoted reads a Go-quoted string from the beginning of s and returns
+// the unquoted value and the remainder of s.
+func readQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", fmt.Errorf("expected quoted identifier in %q", s)
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", err
+			}
+			return value, s[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated quoted identifier in %q", s)
+}
+
+// parents returns the direct dependencies of every node keyed by node ID.
+func (g Graph) parents() map[string][]string {
+	parents := make(map[string][]string, len(g.Nodes))
+	for _, edge := range g.Edges {
+		parents[edge.To] = append(parents[edge.To], edge.From)
+	}
+	return parents
+}
+
+// children returns the direct dependants of every node keyed by node ID.
+func (g Graph) children() map[string][]string {
+	children := make(map[string][]string, len(g.Nodes))
+	for _, edge := range g.Edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+	}
+	return children
+}
+
+// HasNode reports whether the graph contains a node with the given ID.
+func (g Graph) HasNode(id string) bool {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// reachable returns every node reachable from id through adjacency,
+// excluding id itself, in sorted order.
+func reachable(adjacency map[string][]string, id string) []string {
+	visited := map[string]struct{}{id: {}}
+	queue := fifoQueue[string]{}
+	queue.Push(id)
+	result := make([]string, 0)
+	for !queue.IsEmpty() {
+		node, _ := queue.Pop()
+		for _, next := range adjacency[node] {
+			if _, ok := visited[next]; ok {
+				continue
+			}
+			visited[next] = struct{}{}
+			result = append(result, next)
+			queue.Push(next)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Ancestors returns the transitive dependencies (parents) of the node id.
+func (g Graph) Ancestors(id string) []string {
+	return reachable(g.parents(), id)
+}
+
+// Descendants returns the transitive dependants (children) of the node id.
+func (g Graph) Descendants(id string) []string {
+	return reachable(g.children(), id)
+}
+
+// Path returns the shortest dependency chain explaining why from depends on to.
+// The result starts with from and ends with to; each element depends on the
+// next one. It returns nil if from does not depend on to.
+func (g Graph) Path(from, to string) []string {
+	parents := g.parents()
+	prev := map[string]string{from: ""}
+	queue := fifoQueue[string]{}
+	queue.Push(from)
+	for !queue.IsEmpty() {
+		node, _ := queue.Pop()
+		if node == to {
+			path := make([]string, 0)
+			for ; node != ""; node = prev[node] {
+				path = append(path, node)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+
+		next := append([]string(nil), parents[node]...)
+		sort.Strings(next)
+		for _, parent := range next {
+			if _, ok := prev[parent]; ok {
+				continue
+			}
+			prev[parent] = node
+			queue.Push(parent)
+		}
+	}
+
+	return nil
+}
+
+// Waves groups nodes into startup layers. Nodes in the first wave have no
+// dependencies; nodes in every following wave depend only on nodes from
+// earlier waves. Nodes that are part of a cycle are never scheduled and are
+// omitted from the result.
+func (g Graph) Waves() [][]string {
+	parents := g.parents()
+	children := g.children()
+	pending := make(map[string]int, len(g.Nodes))
+	for _, node := range g.Nodes {
+		pending[node.ID] = len(parents[node.ID])
+	}
+
+	waves := make([][]string, 0)
+	wave := make([]string, 0)
+	for id, n := range pending {
+		if n == 0 {
+			wave = append(wave, id)
+		}
+	}
+
+	for len(wave) > 0 {
+		sort.Strings(wave)
+		waves = append(waves, wave)
+		next := make([]string, 0)
+		for _, id := range wave {
+			for _, child := range children[id] {
+				pending[child]--
+				if pending[child] == 0 {
+					next = append(next, child)
+				}
+			}
+		}
+		wave = next
+	}
+
+	return waves
+}
+
+// Cycles returns every strongly connected group of nodes that forms a
+// dependency cycle, including nodes that depend on themselves.
+func (g Graph) Cycles() [][]string {
+	children := g.children()
+	index := make(map[string]int, len(g.Nodes))
+	low := make(map[string]int, len(g.Nodes))
+	onStack := make(map[string]bool, len(g.Nodes))
+	stack := lifoQueue[string]{}
+	cycles := make([][]string, 0)
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack.Push(id)
+		onStack[id] = true
+
+		selfLoop := false
+		for _, child := range children[id] {
+			if child == id {
+				selfLoop = true
+			}
+			if _, ok := index[child]; !ok {
+				visit(child)
+				low[id] = min(low[id], low[child])
+			} else if onStack[child] {
+				low[id] = min(low[id], index[child])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+
+		component := make([]string, 0)
+		for {
+			node, _ := stack.Pop()
+			onStack[node] = false
+			component = append(component, node)
+			if node == id {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	ids := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, ok := index[id]; !ok {
+			visit(id)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// GraphDiff describes the difference between two dependency graphs.
+type GraphDiff struct {
+	AddedNodes   []string    `json:"added_nodes"`
+	RemovedNodes []string    `json:"removed_nodes"`
+	AddedEdges   []GraphEdge `json:"added_edges"`
+	RemovedEdges []GraphEdge `json:"removed_edges"`
+}
+
+// IsEmpty returns true if both graphs have the same nodes and edges.
+func (d GraphDiff) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// DiffGraphs compares two graphs and reports the nodes and edges present
+// only in newGraph (added) or only in oldGraph (removed). Edge labels are ignored.
+func DiffGraphs(oldGraph, newGraph Graph) GraphDiff {
+	nodeSet := func(g Graph) map[string]struct{} {
+		set := make(map[string]struct{}, len(g.Nodes))
+		for _, node := range g.Nodes {
+			set[node.ID] = struct{}{}
+		}
+		return set
+	}
+	edgeSet := func(g Graph) map[GraphEdge]struct{} {
+		set := make(map[GraphEdge]struct{}, len(g.Edges))
+		for _, edge := range g.Edges {
+			set[GraphEdge{From: edge.From, To: edge.To}] = struct{}{}
+		}
+		return set
+	}
+
+	oldNodes, newNodes := nodeSet(oldGraph), nodeSet(newGraph)
+	oldEdges, newEdges := edgeSet(oldGraph), edgeSet(newGraph)
+	diff := GraphDiff{
+		AddedNodes:   missingKeys(newNodes, oldNodes),
+		RemovedNodes: missingKeys(oldNodes, newNodes),
+		AddedEdges:   missingKeys(newEdges, oldEdges),
+		RemovedEdges: missingKeys(oldEdges, newEdges),
+	}
+
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	sortEdges(diff.AddedEdges)
+	sortEdges(diff.RemovedEdges)
+	return diff
+}
+
+// missingKeys returns the keys of a that are not present in b.
+func missingKeys[K comparable](a, b map[K]struct{}) []K {
+	keys := make([]K, 0)
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sortEdges orders edges by source and then by target node.
+func sortEdges(edges []GraphEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
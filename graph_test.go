@@ -496,3 +496,140 @@ func TestGraph_ToDOT_ComplexDependencies(t *testing.T) {
 	assert.Contains(t, dot, `"B" -> "D"`)
 	assert.Contains(t, dot, `"C" -> "D"`)
 }
+
+// queryGraph returns db <- cache <- api, db <- worker graph used by query tests.
+func queryGraph() Graph {
+	return Graph{
+		Nodes: []GraphNode{{ID: "db"}, {ID: "cache"}, {ID: "api"}, {ID: "worker"}},
+		Edges: []GraphEdge{
+			{From: "db", To: "cache"},
+			{From: "cache", To: "api"},
+			{From: "db", To: "worker"},
+		},
+	}
+}
+
+// Test: ParseGraph reads back both DOT and JSON exports
+func TestParseGraph_RoundTrip(t *testing.T) {
+	graph := queryGraph()
+	graph.Nodes = append(graph.Nodes, GraphNode{ID: `quoted "node"`})
+	graph.Edges = append(graph.Edges, GraphEdge{From: "api", To: `quoted "node"`, Label: "uses"})
+
+	parsed, err := ParseGraph([]byte(graph.ToDOT()))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, graph.Nodes, parsed.Nodes)
+	assert.ElementsMatch(t, graph.Edges, parsed.Edges)
+
+	data, err := graph.ToJSON()
+	require.NoError(t, err)
+	parsed, err = ParseGraph(data)
+	require.NoError(t, err)
+	assert.Equal(t, graph, parsed)
+}
+
+// Test: ParseGraph rejects unknown input
+func TestParseGraph_Invalid(t *testing.T) {
+	_, err := ParseGraph([]byte("graph"))
+	assert.Error(t, err)
+
+	_, err = ParseGraph([]byte("{"))
+	assert.Error(t, err)
+
+	_, err = ParseGraph([]byte("digraph G {\n  \"unterminated;\n}"))
+	assert.Error(t, err)
+}
+
+// Test: Ancestors and Descendants return transitive relations
+func TestGraph_AncestorsDescendants(t *testing.T) {
+	graph := queryGraph()
+
+	assert.Equal(t, []string{"cache", "db"}, graph.Ancestors("api"))
+	assert.Equal(t, []string{"api", "cache", "worker"}, graph.Descendants("db"))
+	assert.Empty(t, graph.Ancestors("db"))
+	assert.Empty(t, graph.Descendants("api"))
+}
+
+// Test: Path explains why one node depends on another
+func TestGraph_Path(t *testing.T) {
+	graph := queryGraph()
+
+	assert.Equal(t, []string{"api", "cache", "db"}, graph.Path("api", "db"))
+	assert.Equal(t, []string{"db"}, graph.Path("db", "db"))
+	assert.Nil(t, graph.Path("db", "api"))
+	assert.Nil(t, graph.Path("worker", "cache"))
+}
+
+// Test: Waves groups nodes into startup layers
+func TestGraph_Waves(t *testing.T) {
+	graph := queryGraph()
+
+	assert.Equal(t, [][]string{{"db"}, {"cache", "worker"}, {"api"}}, graph.Waves())
+}
+
+// Test: Cycles reports strongly connected groups
+func TestGraph_Cycles(t *testing.T) {
+	graph := queryGraph()
+	assert.Empty(t, graph.Cycles())
+
+	graph.Edges = append(graph.Edges,
+		GraphEdge{From: "api", To: "db"},
+		GraphEdge{From: "worker", To: "worker"},
+	)
+	assert.Equal(t, [][]string{{"api", "cache", "db"}, {"worker"}}, graph.Cycles())
+	assert.Empty(t, graph.Waves())
+}
+
+// Test: DiffGraphs reports added and removed nodes and edges
+func TestDiffGraphs(t *testing.T) {
+	oldGraph := queryGraph()
+	newGraph := Graph{
+		Nodes: []GraphNode{{ID: "db"}, {ID: "cache"}, {ID: "api"}, {ID: "queue"}},
+		Edges: []GraphEdge{
+			{From: "db", To: "cache", Label: "ignored"},
+			{From: "cache", To: "api"},
+			{From: "queue", To: "api"},
+		},
+	}
+
+	diff := DiffGraphs(oldGraph, newGraph)
+	assert.Equal(t, []string{"queue"}, diff.AddedNodes)
+	assert.Equal(t, []string{"worker"}, diff.RemovedNodes)
+	assert.Equal(t, []GraphEdge{{From: "queue", To: "api"}}, diff.AddedEdges)
+	assert.Equal(t, []GraphEdge{{From: "db", To: "worker"}}, diff.RemovedEdges)
+	assert.False(t, diff.IsEmpty())
+	assert.True(t, DiffGraphs(oldGraph, oldGraph).IsEmpty())
+}
+
+// Test: ToMermaid renders a flowchart
+func TestGraph_ToMermaid(t *testing.T) {
+	graph := Graph{
+		Nodes: []GraphNode{{ID: "db"}, {ID: "api"}},
+		Edges: []GraphEdge{{From: "db", To: "api", Label: "sql"}},
+	}
+
+	mermaid := graph.ToMermaid()
+	assert.Contains(t, mermaid, "flowchart TB\n")
+	assert.Contains(t, mermaid, `n0["db"]`)
+	assert.Contains(t, mermaid, `n1["api"]`)
+	assert.Contains(t, mermaid, `n0 -->|"sql"| n1`)
+}
+
+// Test: writeGraphToFile writes JSON for .json files
+func TestLifecycle_WriteGraphToFile_JSON(t *testing.T) {
+	tempFile := t.TempDir() + "/graph.json"
+	lc := NewLifecycle(testLogger{}, WithGraphOutput(tempFile)).(*lifecycle)
+
+	a := &componentA{}
+	b := &componentB{a: a}
+	lc.Register(a)
+	lc.Register(b)
+
+	require.NoError(t, lc.writeGraphToFile())
+
+	content, err := os.ReadFile(tempFile)
+	require.NoError(t, err)
+	graph, err := ParseGraph(content)
+	require.NoError(t, err)
+	assert.Len(t, graph.Nodes, 2)
+	assert.Equal(t, []GraphEdge{{From: "*goscade.componentA", To: "*goscade.componentB"}}, graph.Edges)
+}
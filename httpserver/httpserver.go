@@ -0,0 +1,204 @@
+// Package httpserver provides a goscade component that runs an *http.Server.
+//
+// The component listens before it reports readiness, so readiness means the
+// port is open. It stops reporting ready through ReadinessHandler when the
+// lifecycle drains it, and once it is asked to stop it shuts the server down
+// within the component's remaining shutdown budget.
+//
+//	srv := httpserver.New(&http.Server{Addr: ":8080", Handler: mux})
+//	mux.Handle("/readyz", srv.ReadinessHandler())
+//	lc.Register(srv)
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/tlsutil"
+)
+
+// Option configures the server.
+type Option func(*Server)
+
+// WithTLS serves HTTPS with the certificate and key in the given files. They
+// are loaded before every run reports readiness, so a missing or invalid
+// key pair fails the run in goscade.PhaseReadiness. The server also serves
+// HTTPS without WithTLS if its TLSConfig holds a certificate.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithShutdownTimeout gives the server its own shutdown budget instead of
+// the lifecycle shutdown timeout. See goscade.ShutdownTimeoutProvider.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// Server is a goscade.Component running an *http.Server. It implements
+// goscade.Drainer.
+type Server struct {
+	srv      *http.Server
+	address  string
+	useTLS   bool
+	certFile string
+	keyFile  string
+
+	shutdownTimeout time.Duration
+
+	ready atomic.Bool
+	mu    sync.Mutex
+	addr  net.Addr
+}
+
+// New returns a component running srv. If srv.Addr is empty, the server
+// listens on ":http", or ":https" when serving HTTPS.
+//
+// An *http.Server cannot serve again after it has been shut down, so every
+// run serves a new http.Server configured like srv. The component can
+// therefore be restarted, but srv itself is never served: calling its
+// Shutdown or Close has no effect on the component.
+func New(srv *http.Server, opts ...Option) *Server {
+	s := &Server{srv: srv, address: srv.Addr}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.useTLS = s.certFile != "" || tlsutil.HasCertificate(srv.TLSConfig)
+	if s.address == "" {
+		s.address = ":http"
+		if s.useTLS {
+			s.address = ":https"
+		}
+	}
+	return s
+}
+
+// Run listens on the server address, reports readiness and serves until ctx
+// is done. It then shuts the server down with goscade.ShutdownContext and
+// closes the remaining connections if the budget runs out. Failures to listen
+// or serve are returned.
+func (s *Server) Run(ctx context.Context, readinessProbe func(cause error)) error {
+	srv, err := s.newHTTPServer()
+	if err != nil {
+		readinessProbe(err)
+		return err
+	}
+	ln, err := net.Listen("tcp", s.address)
+	if err != nil {
+		err = fmt.Errorf("listen on %s: %w", s.address, err)
+		readinessProbe(err)
+		return err
+	}
+	s.mu.Lock()
+	s.addr = ln.Addr()
+	s.mu.Unlock()
+
+	err = goscade.ServeListener(ctx, ln, &listenServer{s: s, srv: srv}, func(cause error) {
+		s.ready.Store(cause == nil)
+		readinessProbe(cause)
+	})
+	s.ready.Store(false)
+	return err
+}
+
+// newHTTPServer returns an http.Server configured like s.srv, holding the
+// key pair set with WithTLS.
+func (s *Server) newHTTPServer() (*http.Server, error) {
+	srv := &http.Server{
+		Addr:                         s.srv.Addr,
+		Handler:                      s.srv.Handler,
+		DisableGeneralOptionsHandler: s.srv.DisableGeneralOptionsHandler,
+		TLSConfig:                    s.srv.TLSConfig.Clone(),
+		ReadTimeout:                  s.srv.ReadTimeout,
+		ReadHeaderTimeout:            s.srv.ReadHeaderTimeout,
+		WriteTimeout:                 s.srv.WriteTimeout,
+		IdleTimeout:                  s.srv.IdleTimeout,
+		MaxHeaderBytes:               s.srv.MaxHeaderBytes,
+		TLSNextProto:                 s.srv.TLSNextProto,
+		ConnState:                    s.srv.ConnState,
+		ErrorLog:                     s.srv.ErrorLog,
+		BaseContext:                  s.srv.BaseContext,
+		ConnContext:                  s.srv.ConnContext,
+	}
+	copyProtocols(srv, s.srv)
+	if s.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load key pair: %w", err)
+		}
+		if srv.TLSConfig == nil {
+			srv.TLSConfig = &tls.Config{}
+		}
+		srv.TLSConfig.Certificates = []tls.Certificate{cert}
+	}
+	return srv, nil
+}
+
+// listenServer serves a single run of Server.
+type listenServer struct {
+	s   *Server
+	srv *http.Server
+}
+
+func (l *listenServer) Serve(ln net.Listener) error {
+	var err error
+	if l.s.useTLS {
+		err = l.srv.ServeTLS(ln, "", "")
+	} else {
+		err = l.srv.Serve(ln)
+	}
+	return fmt.Errorf("serve: %w", err)
+}
+
+func (l *listenServer) Shutdown(ctx context.Context) error {
+	l.s.ready.Store(false)
+	if err := l.srv.Shutdown(ctx); err != nil {
+		return errors.Join(fmt.Errorf("shutdown: %w", err), l.srv.Close())
+	}
+	return nil
+}
+
+// ShutdownTimeout returns the budget set with WithShutdownTimeout.
+func (s *Server) ShutdownTimeout() time.Duration {
+	return s.shutdownTimeout
+}
+
+// Drain makes ReadinessHandler report the server as not ready, so load
+// balancers stop routing new requests to it before it shuts down.
+func (s *Server) Drain(context.Context) error {
+	s.ready.Store(false)
+	return nil
+}
+
+// Addr returns the address the server listens on, or nil before Run has
+// started listening. It is useful when srv.Addr has port 0.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// ReadinessHandler returns a handler responding 200 OK while the server is
+// serving and 503 Service Unavailable before it is ready and once it is
+// draining or stopping.
+func (s *Server) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !s.ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+}
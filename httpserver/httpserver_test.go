@@ -0,0 +1,207 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_ServesAndShutsDown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})
+	srv := New(&http.Server{Addr: "127.0.0.1:0", Handler: mux})
+	mux.Handle("/readyz", srv.ReadinessHandler())
+	assert.Nil(t, srv.Addr())
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	url := "http://" + srv.Addr().String()
+	status, body := get(t, http.DefaultClient, url)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello", body)
+	status, _ = get(t, http.DefaultClient, url+"/readyz")
+	assert.Equal(t, http.StatusOK, status)
+
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+	_, err := http.Get(url)
+	assert.Error(t, err)
+}
+
+func TestServer_Restart(t *testing.T) {
+	srv := New(&http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})})
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, lc.Restart(srv))
+		status, body := get(t, http.DefaultClient, "http://"+srv.Addr().String())
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello", body)
+	}
+	assert.Equal(t, goscade.LifecycleStatusReady, lc.Status())
+
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+}
+
+func TestServer_DrainFlipsReadiness(t *testing.T) {
+	srv := New(&http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()})
+	readiness := httptest.NewServer(srv.ReadinessHandler())
+	defer readiness.Close()
+
+	status, _ := get(t, readiness.Client(), readiness.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	status, _ = get(t, readiness.Client(), readiness.URL)
+	assert.Equal(t, http.StatusOK, status)
+
+	require.NoError(t, srv.Drain(context.Background()))
+	status, _ = get(t, readiness.Client(), readiness.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	cancel()
+	<-done
+}
+
+func TestServer_ListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(New(&http.Server{Addr: ln.Addr().String()}))
+	err = lc.Run(context.Background(), nil)
+
+	componentErrs := goscade.ComponentErrors(err)
+	require.Len(t, componentErrs, 1)
+	assert.Equal(t, goscade.PhaseReadiness, componentErrs[0].Phase)
+	assert.ErrorContains(t, err, "listen on "+ln.Addr().String())
+}
+
+func TestServer_TLS(t *testing.T) {
+	// Borrow the test certificate and a client trusting it from httptest
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	client := ts.Client()
+	cert := ts.TLS.Certificates
+	ts.Close()
+
+	srv := New(&http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}),
+		TLSConfig: ts.TLS.Clone(),
+	})
+	srv.srv.TLSConfig.Certificates = cert
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	status, _ := get(t, client, "https://"+srv.Addr().String())
+	assert.Equal(t, http.StatusOK, status)
+
+	require.NoError(t, lc.Restart(srv))
+	status, _ = get(t, client, "https://"+srv.Addr().String())
+	assert.Equal(t, http.StatusOK, status)
+
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+}
+
+func TestServer_InvalidKeyPairFailsReadiness(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "missing.crt")
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(New(&http.Server{Addr: "127.0.0.1:0"}, WithTLS(certFile, certFile)))
+	err := lc.Run(context.Background(), nil)
+
+	componentErrs := goscade.ComponentErrors(err)
+	require.Len(t, componentErrs, 1)
+	assert.Equal(t, goscade.PhaseReadiness, componentErrs[0].Phase)
+	assert.ErrorContains(t, err, "load key pair")
+}
+
+func TestServer_TLSConfigWithoutCertificateServesHTTP(t *testing.T) {
+	srv := New(&http.Server{
+		Addr:      "127.0.0.1:0",
+		Handler:   http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	})
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	status, _ := get(t, http.DefaultClient, "http://"+srv.Addr().String())
+	assert.Equal(t, http.StatusOK, status)
+
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+}
+
+func TestServer_ShutdownClosesConnectionsAfterBudget(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv := New(&http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	})}, WithShutdownTimeout(100*time.Millisecond))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(srv)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	go func() {
+		resp, err := http.Get("http://" + srv.Addr().String())
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	cancel()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not respect the server budget")
+	}
+	componentErrs := goscade.ComponentErrors(err)
+	require.NotEmpty(t, componentErrs)
+	for _, componentErr := range componentErrs {
+		assert.Equal(t, goscade.PhaseShutdown, componentErr.Phase)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !go1.24
+
+package httpserver
+
+import "net/http"
+
+// copyProtocols copies the protocol settings of src to dst. Go releases
+// before 1.24 have none beyond those copied by newHTTPServer.
+func copyProtocols(_, _ *http.Server) {}
@@ -0,0 +1,11 @@
+//go:build go1.24
+
+package httpserver
+
+import "net/http"
+
+// copyProtocols copies the protocol settings added in Go 1.24 from src to dst.
+func copyProtocols(dst, src *http.Server) {
+	dst.HTTP2 = src.HTTP2
+	dst.Protocols = src.Protocols
+}
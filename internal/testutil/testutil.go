@@ -0,0 +1,115 @@
+// Package testutil provides helpers shared by the tests of goscade and its
+// component packages. It does not import goscade, so that the tests of the
+// goscade package itself can use it.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readyTimeout bounds how long RunLifecycle waits for the lifecycle to become
+// ready. It leaves room for components that start subprocesses.
+const readyTimeout = 10 * time.Second
+
+// NopLogger is a goscade.PrintfLogger that discards everything.
+type NopLogger struct{}
+
+func (NopLogger) Infof(string, ...any)  {}
+func (NopLogger) Errorf(string, ...any) {}
+
+// RecordingLogger is a goscade.PrintfLogger that records the formatted lines
+// logged through it. It is safe for concurrent use.
+type RecordingLogger struct {
+	mu     sync.Mutex
+	lines  []string
+	infos  []string
+	errors []string
+}
+
+func (l *RecordingLogger) Infof(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := fmt.Sprintf(format, args...)
+	l.lines = append(l.lines, line)
+	l.infos = append(l.infos, line)
+}
+
+func (l *RecordingLogger) Errorf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := fmt.Sprintf(format, args...)
+	l.lines = append(l.lines, line)
+	l.errors = append(l.errors, line)
+}
+
+// Infos returns the lines logged with Infof.
+func (l *RecordingLogger) Infos() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.infos...)
+}
+
+// Errors returns the lines logged with Errorf.
+func (l *RecordingLogger) Errors() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.errors...)
+}
+
+// String returns every logged line in order, one per line.
+func (l *RecordingLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+// SyncBuffer is a bytes.Buffer safe for concurrent writes.
+type SyncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *SyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *SyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Runner is the part of goscade.Lifecycle used by RunLifecycle.
+type Runner interface {
+	Run(ctx context.Context, readinessProbe func(err error)) error
+}
+
+// RunLifecycle runs lc in the background and fails the test unless it becomes
+// ready. It returns the function cancelling the run and the channel receiving
+// the error Run returns.
+func RunLifecycle(t testing.TB, lc Runner) (context.CancelFunc, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- lc.Run(ctx, func(err error) { ready <- err })
+	}()
+	select {
+	case err := <-ready:
+		require.NoError(t, err)
+	case <-time.After(readyTimeout):
+		t.Fatal("lifecycle did not become ready")
+	}
+	return cancel, done
+}
@@ -0,0 +1,11 @@
+// Package tlsutil provides TLS helpers shared by goscade and its component
+// packages.
+package tlsutil
+
+import "crypto/tls"
+
+// HasCertificate reports whether cfg provides a certificate, as
+// http.Server.ServeTLS requires when given no certificate files.
+func HasCertificate(cfg *tls.Config) bool {
+	return cfg != nil && (len(cfg.Certificates) > 0 || cfg.GetCertificate != nil || cfg.GetConfigForClient != nil)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os/signal"
+	"log/slog"
+	"os"
 	"reflect"
+	"runtime/trace"
+	"sort"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -27,13 +30,36 @@ var (
 	// ShutdownTimeoutError is returned when components do not stop before the
 	// configured shutdown timeout.
 	ShutdownTimeoutError = errors.New("shutdown timeout")
+
+	// LifecycleNotReadyError is returned when a component is stopped, started
+	// or reloaded while the lifecycle is not ready.
+	LifecycleNotReadyError = errors.New("lifecycle is not ready")
+
+	// UnregisteredComponentError is returned when an operation refers to a
+	// component that has not been registered.
+	UnregisteredComponentError = errors.New("unregistered component")
+
+	// DependencyStoppedError is returned when a component cannot start because
+	// a component it depends on has been stopped.
+	DependencyStoppedError = errors.New("dependency stopped")
+
+	// ForcedShutdownError is returned when a second shutdown signal is
+	// received while the lifecycle is stopping. Run returns immediately
+	// without waiting for the remaining components to stop.
+	ForcedShutdownError = errors.New("forced shutdown")
+
+	// NotRestartableError is returned when a component that can only run once,
+	// such as one created by FromListener, is started again.
+	NotRestartableError = errors.New("component cannot be restarted")
+
+	// GoroutineLeakError is returned when goroutines started by components are
+	// still running after all components have stopped. See WithLeakCheck.
+	GoroutineLeakError = errors.New("goroutine leak")
 )
 
-// logger defines the interface for logging within the lifecycle system.
-type logger interface {
-	Infof(format string, args ...interface{})
-	Errorf(format string, args ...interface{})
-}
+// leakCheckGracePeriod is how long the leak check waits for goroutines that
+// are still exiting after their component has stopped.
+const leakCheckGracePeriod = 500 * time.Millisecond
 
 // Component represents a component that can be managed by the lifecycle system.
 // Each component must implement the Run method which will be called by the
@@ -42,10 +68,44 @@ type Component interface {
 	// Run starts the component with the provided context and readiness probe.
 	// The readinessProbe function should be called when the component is ready
 	// to serve requests. If called with an error, the component will be marked
-	// as failed and the lifecycle will initiate a shutdown.
+	// as failed and the lifecycle will initiate a shutdown. The context carries
+	// the component's metadata; see ComponentName and Logger.
 	Run(ctx context.Context, readinessProbe func(cause error)) error
 }
 
+// StartTimeoutProvider can be implemented by a component that needs a start
+// timeout different from the one configured with WithStartTimeout. A
+// non-positive duration falls back to the lifecycle default.
+type StartTimeoutProvider interface {
+	// StartTimeout returns the maximum time the component may take to become ready.
+	StartTimeout() time.Duration
+}
+
+// ShutdownTimeoutProvider can be implemented by a component that needs its own
+// shutdown budget. The budget starts when the lifecycle asks the component to
+// stop; if Run has not returned by then, the component is reported with a
+// ShutdownTimeoutError and the components it depends on are stopped without
+// waiting for it any longer. The lifecycle shutdown timeout is extended to
+// cover the budgets of a whole chain of dependants. A non-positive duration
+// disables the per-component budget and only the lifecycle shutdown timeout
+// applies.
+type ShutdownTimeoutProvider interface {
+	// ShutdownTimeout returns the maximum time the component may take to stop.
+	ShutdownTimeout() time.Duration
+}
+
+// LeakCheckMode selects how WithLeakCheck reports leaked goroutines.
+type LeakCheckMode string
+
+const (
+	// LeakCheckLog logs leaked goroutines without affecting the result of Run.
+	LeakCheckLog LeakCheckMode = "log"
+
+	// LeakCheckError additionally returns a *LeakedGoroutinesError from Run.
+	// It is intended for tests.
+	LeakCheckError LeakCheckMode = "error"
+)
+
 // LifecycleStatus represents the current state of the lifecycle manager.
 type LifecycleStatus string
 
@@ -59,6 +119,11 @@ const (
 	// LifecycleStatusReady indicates all components are running and ready.
 	LifecycleStatusReady LifecycleStatus = "ready"
 
+	// LifecycleStatusDraining indicates shutdown has been requested and
+	// components implementing Drainer are finishing in-flight work. Components
+	// are not stopped yet, but the lifecycle no longer reports itself ready.
+	LifecycleStatusDraining LifecycleStatus = "draining"
+
 	// LifecycleStatusStopping indicates components are shutting down.
 	LifecycleStatusStopping LifecycleStatus = "stopping"
 
@@ -93,30 +158,77 @@ type Lifecycle interface {
 	// Run starts all registered components and blocks until shutdown.
 	// The method handles dependency resolution, concurrent startup, and graceful shutdown.
 	// The readinessProbe callback is called when all components are ready or if there's an error during startup.
-	// By default, the lifecycle will not respond to system signals unless WithShutdownHook() option is used.
+	// By default, the lifecycle will not respond to system signals unless WithShutdownHook() or WithSignals() is used.
 	// Run panics if no components have been registered.
+	// A panic inside a component's Run is recovered and reported as a *PanicError.
+	// If components do not stop in time, a *StuckComponentsError lists them.
 	// The returned error joins the shutdown cause with independent component errors.
 	Run(ctx context.Context, readinessProbe func(err error)) error
 
 	// Status returns the current status of the lifecycle manager.
 	Status() LifecycleStatus
+
+	// Reload restarts the given components and every component depending on
+	// them while the rest of the graph keeps running. Between stopping and
+	// starting them, it calls the hook set by WithReloadHook and Reload on
+	// every stopped component implementing Reloader.
+	Reload(components ...Component) error
+
+	// Stop stops the component and every component depending on it while the
+	// rest of the graph keeps running.
+	Stop(component Component) error
+
+	// Start starts the component and every stopped component it depends on.
+	Start(component Component) error
+
+	// Restart stops the component and every component depending on it, then
+	// starts them again.
+	Restart(component Component) error
+
+	// Shutdown stops the active Run gracefully, as if its context had been
+	// cancelled.
+	Shutdown() error
+
+	// Snapshot returns the status of the lifecycle and of every component.
+	Snapshot() Snapshot
+
+	// Subscribe returns a channel delivering lifecycle and component status
+	// changes until ctx is done.
+	Subscribe(ctx context.Context) <-chan Event
 }
 
 // lifecycle is the internal implementation of the Lifecycle interface.
 type lifecycle struct {
 	mu                 sync.RWMutex
+	opMu               sync.Mutex
 	status             LifecycleStatus
 	compToImplicitDeps map[Component]map[Component]struct{}
 	compToLinkedDeps   map[Component][]any
 	components         map[Component]struct{}
 	ptrToComp          map[uintptr]Component
-	log                logger
+	log                *slog.Logger
+	id                 string
 
 	ignoreCircularDependency bool
+	disableReflection        bool
 	shutdownHook             bool
+	shutdownSignals          []os.Signal
+	reloadHook               func()
+	statusDumpSignals        []os.Signal
 	startTimeout             time.Duration
 	shutdownTimeout          time.Duration
+	drainTimeout             time.Duration
+	preDrainDelay            time.Duration
+	goroutineDump            bool
+	leakCheck                LeakCheckMode
+	propagateContext         bool
 	graphOutputFile          string
+
+	// run holds the state of the active Run call.
+	run *runState
+	// events delivers status changes to subscribers.
+	events eventBus
+	tracer Tracer
 }
 
 // Option is a function type for configuring lifecycle behavior.
@@ -132,18 +244,58 @@ func WithCircularDependency() Option {
 	}
 }
 
+// WithoutReflection disables reflection-based discovery of dependencies in
+// component fields. Only the implicit dependencies passed to Register and the
+// structs passed to Link are used to build the graph. This is intended for
+// wiring code produced by "goscade gen", which declares every dependency
+// explicitly and does not need to pay for reflective traversal at startup.
+func WithoutReflection() Option {
+	return func(lc *lifecycle) {
+		lc.disableReflection = true
+	}
+}
+
 // WithShutdownHook enables graceful shutdown on system signals (SIGINT, SIGTERM).
 // By default, lifecycles do not respond to system signals and only shut down
 // when the context is cancelled. This option enables signal handling for
 // graceful shutdown on system termination signals.
 func WithShutdownHook() Option {
+	return WithSignals(defaultShutdownSignals...)
+}
+
+// WithSignals enables graceful shutdown on the given signals instead of the
+// default SIGINT and SIGTERM. The received signal is available from the error
+// returned by Run as a *SignalError. If one of the signals is received again
+// while the lifecycle is stopping, Run returns immediately with an error
+// matching ForcedShutdownError.
+func WithSignals(sigs ...os.Signal) Option {
 	return func(lc *lifecycle) {
 		lc.shutdownHook = true
+		lc.shutdownSignals = sigs
+	}
+}
+
+// WithReloadHook sets a hook that Reload calls after the reloaded components
+// have stopped and before they are started again, which is the place to swap
+// configuration. It also makes the lifecycle handle SIGHUP while Run is active
+// by reloading every component implementing Reloader.
+func WithReloadHook(hook func()) Option {
+	return func(lc *lifecycle) {
+		lc.reloadHook = hook
+	}
+}
+
+// WithStatusDump logs the lifecycle status, the status of every component and
+// the dependency graph in DOT format whenever one of sigs is received while
+// Run is active, typically syscall.SIGUSR1.
+func WithStatusDump(sigs ...os.Signal) Option {
+	return func(lc *lifecycle) {
+		lc.statusDumpSignals = sigs
 	}
 }
 
 // WithStartTimeout sets the timeout for component startup and readiness probe.
-// Default is 1 minute.
+// Default is 1 minute. Components implementing StartTimeoutProvider override it.
 func WithStartTimeout(timeout time.Duration) Option {
 	return func(lc *lifecycle) {
 		lc.startTimeout = timeout
@@ -151,15 +303,89 @@ func WithStartTimeout(timeout time.Duration) Option {
 }
 
 // WithShutdownTimeout sets the maximum time to wait for components to stop.
-// Default is 1 minute. Component goroutines cannot be forcibly terminated and
-// may continue running after Run returns.
+// Default is 1 minute. If components implement ShutdownTimeoutProvider, Run
+// waits at least for the sum of their budgets along the longest chain of
+// dependants, since each of them is stopped after its dependants. Component
+// goroutines cannot be forcibly terminated and may continue running after Run
+// returns.
 func WithShutdownTimeout(timeout time.Duration) Option {
 	return func(lc *lifecycle) {
 		lc.shutdownTimeout = timeout
 	}
 }
 
-// WithGraphOutput enables writing the dependency graph to a file in DOT format.
+// WithDrainTimeout sets the maximum time components implementing Drainer may
+// spend draining before the cascade shutdown starts. Default is 30 seconds.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(lc *lifecycle) {
+		lc.drainTimeout = timeout
+	}
+}
+
+// WithPreDrainDelay sets how long the lifecycle stays in LifecycleStatusDraining
+// before calling Drain, giving load balancers time to observe the failing
+// readiness check and stop routing traffic. Default is zero.
+func WithPreDrainDelay(delay time.Duration) Option {
+	return func(lc *lifecycle) {
+		lc.preDrainDelay = delay
+	}
+}
+
+// WithGoroutineDump attaches the goroutines of every component that has not
+// stopped when the shutdown timeout fires to the returned StuckComponentsError
+// and logs them. Goroutines are matched by the ComponentLabel pprof label, so
+// only goroutines started from within the component's Run are included.
+func WithGoroutineDump() Option {
+	return func(lc *lifecycle) {
+		lc.goroutineDump = true
+	}
+}
+
+// WithLeakCheck enables goroutine leak detection after all components have
+// stopped. Goroutines started from a component's Run inherit its ComponentLabel
+// pprof label; any of them still running shortly after shutdown is reported
+// per component according to mode. The check is skipped if the shutdown
+// timeout fires. Labels are matched by component name, so goroutines of
+// another lifecycle running in the same process with identically named
+// components are attributed to this one as well.
+func WithLeakCheck(mode LeakCheckMode) Option {
+	return func(lc *lifecycle) {
+		lc.leakCheck = mode
+	}
+}
+
+// WithEventHandler calls handler for every Event, in order, as it happens.
+// Unlike Subscribe, no event is ever dropped, but the handler is called with
+// internal locks held: it must return quickly and must not call methods of
+// the Lifecycle.
+func WithEventHandler(handler func(Event)) Option {
+	return func(lc *lifecycle) {
+		lc.events.handlers = append(lc.events.handlers, handler)
+	}
+}
+
+// WithTracer records the startup and the shutdown performed by Run as span
+// trees using tracer: a SpanLifecycleStart span with a child span per
+// component, and a matching SpanLifecycleShutdown tree. Spans are children of
+// the span in the context passed to Run, if any.
+func WithTracer(tracer Tracer) Option {
+	return func(lc *lifecycle) {
+		lc.tracer = tracer
+	}
+}
+
+// WithContextPropagation makes the contexts passed to components carry the
+// values of the context passed to Run, such as trace IDs or request-scoped
+// configuration. Cancellation is not propagated: components are still stopped
+// by the cascade shutdown, in dependency order.
+func WithContextPropagation() Option {
+	return func(lc *lifecycle) {
+		lc.propagateContext = true
+	}
+}
+
+// WithGraphOutput enables writing the dependency graph to a file in DOT format,
+// or in JSON format if filename has a .json extension.
 // The file will be written when the lifecycle starts running.
 // Use Graphviz (e.g., dot -Tpng graph.dot -o graph.png) to visualize the output.
 func WithGraphOutput(filename string) Option {
@@ -170,10 +396,13 @@ func WithGraphOutput(filename string) Option {
 
 // NewLifecycle creates a new lifecycle manager with the provided logger and options.
 // The lifecycle manager will handle component registration, dependency resolution,
-// and graceful shutdown of all registered components.
-func NewLifecycle(log logger, opts ...Option) Lifecycle {
+// and graceful shutdown of all registered components. The logger is wrapped with
+// NewPrintfHandler; use WithLogger or WithLogHandler to log structured records
+// instead. A nil logger logs to slog.Default().
+func NewLifecycle(log PrintfLogger, opts ...Option) Lifecycle {
 	lc := &lifecycle{
-		log:                log,
+		log:                slog.Default(),
+		id:                 newLifecycleID(),
 		status:             LifecycleStatusIdle,
 		compToImplicitDeps: make(map[Component]map[Component]struct{}),
 		compToLinkedDeps:   make(map[Component][]any),
@@ -181,11 +410,17 @@ func NewLifecycle(log logger, opts ...Option) Lifecycle {
 		ptrToComp:          make(map[uintptr]Component),
 		startTimeout:       time.Minute, // Default 1 minute
 		shutdownTimeout:    time.Minute, // Default 1 minute
+		drainTimeout:       30 * time.Second,
+		tracer:             noopTracer{},
+	}
+	if log != nil {
+		lc.log = slog.New(NewPrintfHandler(log, nil))
 	}
 
 	for _, opt := range opts {
 		opt(lc)
 	}
+	lc.log = lc.log.With(LogKeyLifecycle, lc.id)
 
 	return lc
 }
@@ -230,17 +465,24 @@ func (lc *lifecycle) setStatus(newStatus LifecycleStatus) bool {
 	lc.mu.Lock()
 	defer lc.mu.Unlock()
 	switch newStatus {
-	case LifecycleStatusStopping:
+	case LifecycleStatusDraining:
 		if lc.status != LifecycleStatusRunning && lc.status != LifecycleStatusReady {
 			return false
 		}
+	case LifecycleStatusStopping:
+		if lc.status != LifecycleStatusRunning && lc.status != LifecycleStatusReady && lc.status != LifecycleStatusDraining {
+			return false
+		}
 	case LifecycleStatusReady:
 		if lc.status != LifecycleStatusRunning {
 			return false
 		}
 	}
 
-	lc.status = newStatus
+	if lc.status != newStatus {
+		lc.status = newStatus
+		lc.events.publish(Event{Time: time.Now(), LifecycleStatus: newStatus})
+	}
 	return true
 }
 
@@ -251,6 +493,22 @@ func (lc *lifecycle) Status() LifecycleStatus {
 	return lc.status
 }
 
+// Shutdown cancels the active Run with context.Canceled, which stops all
+// components gracefully. It returns LifecycleNotReadyError if Run has not been
+// called.
+func (lc *lifecycle) Shutdown() error {
+	lc.mu.RLock()
+	r := lc.run
+	lc.mu.RUnlock()
+	if r == nil {
+		return LifecycleNotReadyError
+	}
+
+	lc.log.Info("shutdown requested")
+	r.lifecycleCtxCancel(context.Canceled)
+	return nil
+}
+
 // componentName returns the display name for a component.
 func (lc *lifecycle) componentName(comp Component) string {
 	if a, ok := comp.(delegateNameProvider); ok {
@@ -259,16 +517,226 @@ func (lc *lifecycle) componentName(comp Component) string {
 	return reflect.TypeOf(comp).String()
 }
 
-// componentState holds the runtime state for a component including
-// its contexts, cancellation functions, and synchronization primitives.
+// componentTimeouts returns the start timeout that applies to comp and its
+// own shutdown budget, or zero if it does not declare one. Adapters forward
+// both to their delegate.
+func (lc *lifecycle) componentTimeouts(comp Component) (start, shutdown time.Duration) {
+	start = lc.startTimeout
+	if p, ok := comp.(StartTimeoutProvider); ok && p.StartTimeout() > 0 {
+		start = p.StartTimeout()
+	}
+	if p, ok := comp.(ShutdownTimeoutProvider); ok && p.ShutdownTimeout() > 0 {
+		shutdown = p.ShutdownTimeout()
+	}
+	return start, shutdown
+}
+
+// stopTimeout returns how long a component may take to stop: its own
+// shutdown budget or, when it is stopped manually, the lifecycle shutdown
+// timeout. Zero means that only the shutdown timeout of Run applies.
+func (lc *lifecycle) stopTimeout(state *componentState, manualStop bool) time.Duration {
+	if state.shutdownTimeout <= 0 && manualStop {
+		return lc.shutdownTimeout
+	}
+	return state.shutdownTimeout
+}
+
+// ComponentStatus describes the state of a single component while Run is active.
+type ComponentStatus string
+
+const (
+	// ComponentStatusWaiting indicates the component is waiting for its
+	// dependencies to become ready.
+	ComponentStatusWaiting ComponentStatus = "waiting"
+
+	// ComponentStatusStarting indicates the component is running but has not
+	// reported readiness yet.
+	ComponentStatusStarting ComponentStatus = "starting"
+
+	// ComponentStatusReady indicates the component is running and ready.
+	ComponentStatusReady ComponentStatus = "ready"
+
+	// ComponentStatusCompleted indicates the component has finished its work
+	// after becoming ready, like a task created by NewTask. The components
+	// depending on it keep running.
+	ComponentStatusCompleted ComponentStatus = "completed"
+
+	// ComponentStatusDraining indicates the component is finishing in-flight
+	// work before shutdown. See Drainer.
+	ComponentStatusDraining ComponentStatus = "draining"
+
+	// ComponentStatusStopping indicates the component has been asked to stop.
+	ComponentStatusStopping ComponentStatus = "stopping"
+
+	// ComponentStatusStopped indicates the component has stopped.
+	ComponentStatusStopped ComponentStatus = "stopped"
+
+	// ComponentStatusFailed indicates the component has stopped with an error.
+	ComponentStatusFailed ComponentStatus = "failed"
+)
+
+// componentState holds the runtime state of a component that persists across
+// restarts: its name, timeouts, status and current generation.
 type componentState struct {
-	componentName  string
-	probeCtx       context.Context
-	cancelProbe    context.CancelCauseFunc
-	runCtx         context.Context
-	cancelRun      context.CancelCauseFunc
-	teardownCtx    context.Context
-	cancelTeardown context.CancelCauseFunc
+	componentName   string
+	log             *slog.Logger
+	startTimeout    time.Duration
+	shutdownTimeout time.Duration
+
+	events *eventBus
+
+	mu     sync.Mutex
+	status ComponentStatus
+	gen    *componentGeneration
+}
+
+// setStatus updates the component status. Completed, stopped and failed
+// components keep their status until the component is started again.
+func (s *componentState) setStatus(status ComponentStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.status {
+	case ComponentStatusCompleted, ComponentStatusStopped, ComponentStatusFailed:
+		return
+	}
+	s.updateStatus(status)
+}
+
+// compareAndSetStatus updates the component status only if it equals old.
+func (s *componentState) compareAndSetStatus(old, status ComponentStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != old {
+		return false
+	}
+	s.updateStatus(status)
+	return true
+}
+
+// newError creates a ComponentError for the component and publishes it.
+func (s *componentState) newError(comp Component, phase Phase, err error) *ComponentError {
+	compErr := newComponentError(comp, s.componentName, phase, err)
+	s.events.publish(Event{Time: compErr.Time, Component: s.componentName, Err: compErr})
+	return compErr
+}
+
+// updateStatus sets the component status and publishes an event if it
+// changed. The caller must hold s.mu.
+func (s *componentState) updateStatus(status ComponentStatus) {
+	if s.status == status {
+		return
+	}
+	s.status = status
+	s.events.publish(Event{
+		Time:            time.Now(),
+		Component:       s.componentName,
+		ComponentStatus: status,
+	})
+}
+
+// getStatus returns the component status.
+func (s *componentState) getStatus() ComponentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// current returns the current generation of the component.
+func (s *componentState) current() *componentGeneration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gen
+}
+
+// componentGeneration holds the contexts, cancellation functions and
+// synchronization primitives of a single run of a component. A new generation
+// is created every time the component is started.
+type componentGeneration struct {
+	traceCtx        context.Context
+	probeCtx        context.Context
+	cancelProbe     context.CancelCauseFunc
+	runCtx          context.Context
+	cancelRun       context.CancelCauseFunc
+	teardownCtx     context.Context
+	cancelTeardown  context.CancelCauseFunc
+	stopCtx         context.Context
+	requestStop     context.CancelFunc
+	startedAt       atomic.Int64
+	stopRequestedAt atomic.Int64
+	// startSpan and runSpan are the startup spans of the generation, set by
+	// traceStart and traceRun; stopSpan is set by traceStop.
+	startSpan *tracedSpan
+	runSpan   *tracedSpan
+	stopSpan  atomic.Pointer[tracedSpan]
+	// shutdownDone is closed once the shutdown of the generation has been
+	// traced, which is after teardownCtx is done.
+	shutdownDone chan struct{}
+
+	// manual is set for generations started by Start, Restart or Reload.
+	// Their start failures are returned to the caller instead of stopping
+	// the lifecycle.
+	manual bool
+
+	errMu sync.Mutex
+	err   error
+}
+
+// ready reports whether the generation has signalled readiness.
+func (g *componentGeneration) ready() bool {
+	return errors.Is(context.Cause(g.probeCtx), componentReady)
+}
+
+// setErr records the first error to report to the caller of a manual
+// operation on the generation.
+func (g *componentGeneration) setErr(err error) {
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+// getErr returns the error recorded by setErr.
+func (g *componentGeneration) getErr() error {
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	return g.err
+}
+
+// runState holds the state shared by all components during a single Run.
+type runState struct {
+	lifecycleCtx       context.Context
+	lifecycleCtxCancel context.CancelCauseFunc
+	drainedCtx         context.Context
+	componentCtx       context.Context
+	prober             *errgroup.Group
+	compStates         map[Component]*componentState
+	compToParents      map[Component]map[Component]struct{}
+	compToChildren     map[Component]map[Component]struct{}
+	componentErrs      *componentErrors
+
+	// stopping is set, under lifecycle.mu, once shutdown has been requested.
+	// No generation is started afterwards.
+	stopping bool
+
+	// startSpans are the component spans of the startup trace. shutdownSpans
+	// and shutdownRoot are the shutdown trace; they are set before drainedCtx
+	// is done and must not be read earlier.
+	startSpans    map[Component]*tracedSpan
+	shutdownSpans map[Component]*tracedSpan
+	shutdownRoot  *tracedSpan
+
+	// shutdownTimeout is how long Run waits for the cascade shutdown. The
+	// cascade must end by shutdownDeadline, which is set before drainedCtx is
+	// done and must not be read earlier.
+	shutdownTimeout  time.Duration
+	shutdownDeadline time.Time
+
+	// traceMu guards the spans started below the shutdown spans, which are
+	// ended by Run if their goroutines have not ended them yet.
+	traceMu          sync.Mutex
+	shutdownChildren []*tracedSpan
+	shutdownEnded    bool
 }
 
 type componentErrors struct {
@@ -353,102 +821,297 @@ func waitProbeErr(ctx context.Context) error {
 	return nil
 }
 
-// runComponent starts a component and manages its lifecycle including
-// dependency waiting, readiness probing, and graceful shutdown.
-func (lc *lifecycle) runComponent(
-	lifecycleCtx context.Context,
-	lifecycleCtxCancel context.CancelCauseFunc,
+// startGeneration starts a new generation of a component and manages its
+// lifecycle including dependency waiting, readiness probing, and graceful
+// shutdown. The component starts running once startLatch is closed.
+// Callers other than Run must hold lc.mu and check that r.stopping is unset.
+func (lc *lifecycle) startGeneration(
+	r *runState,
 	comp Component,
-	runner *errgroup.Group,
-	prober *errgroup.Group,
-	compStates map[Component]*componentState,
-	compToParents map[Component]map[Component]struct{},
-	compToChildren map[Component]map[Component]struct{},
-	startLatch chan struct{},
-	componentErrs *componentErrors,
-) {
-	state := compStates[comp]
-	//  Wait until all children have finished successfully, or any of them has failed
-	go func() {
-		for childComp := range compToChildren[comp] {
-			if err := waitCtxErr(compStates[childComp].teardownCtx); err != nil {
-				state.cancelRun(err)
-				break
-			}
+	manual bool,
+	startLatch <-chan struct{},
+) *componentGeneration {
+	state := r.compStates[comp]
+	gen := &componentGeneration{manual: manual, shutdownDone: make(chan struct{})}
+	gen.probeCtx, gen.cancelProbe = context.WithCancelCause(r.lifecycleCtx)
+	runCtx, cancelRun := context.WithCancelCause(withShutdownDeadline(lc.componentContext(r.componentCtx, state), func() time.Time {
+		return lc.shutdownDeadline(r, state, gen)
+	}))
+	gen.runCtx = runCtx
+	gen.cancelRun = func(cause error) {
+		// Mark the component as stopping before its context is cancelled
+		if gen.stopRequestedAt.CompareAndSwap(0, time.Now().UnixNano()) {
+			lc.traceStop(r, comp, gen)
+			state.setStatus(ComponentStatusStopping)
 		}
+		cancelRun(cause)
+	}
+	gen.teardownCtx, gen.cancelTeardown = context.WithCancelCause(context.Background())
+	gen.stopCtx, gen.requestStop = context.WithCancel(context.Background())
+	traceCtx, task := startComponentTrace(gen.runCtx, state.componentName)
+	gen.traceCtx = traceCtx
+
+	state.mu.Lock()
+	state.updateStatus(ComponentStatusWaiting)
+	state.gen = gen
+	state.mu.Unlock()
+
+	go lc.stopAfterChildren(r, comp, gen)
+	go lc.superviseShutdown(r, comp, gen, task)
+	// Manually started generations are awaited by the caller instead
+	if !manual {
+		lc.registerProbe(r, comp, gen)
+	}
+	go lc.runGeneration(r, comp, gen, startLatch)
 
-		state.cancelRun(waitCtxErr(lifecycleCtx))
-	}()
+	return gen
+}
 
-	// Wait until the component's readiness probe signals ready or failed
-	prober.Go(func() error {
-		probeCtx, cancel := context.WithTimeout(state.probeCtx, lc.startTimeout)
-		defer cancel()
+// stopAfterChildren cancels the generation once it is asked to stop and all
+// children have finished successfully, or as soon as any of them has failed.
+func (lc *lifecycle) stopAfterChildren(r *runState, comp Component, gen *componentGeneration) {
+	select {
+	case <-r.drainedCtx.Done():
+	case <-gen.stopCtx.Done():
+	}
 
-		if err := waitProbeErr(probeCtx); err != nil {
-			if state.probeCtx.Err() != nil {
-				return err
-			}
-			probeErr := fmt.Errorf("component %s readiness: %w", state.componentName, err)
-			componentErrs.add(probeErr)
-			lc.log.Errorf("Component %s [PROB ERROR]: %v", state.componentName, err)
-			lifecycleCtxCancel(probeErr)
-			return probeErr
+	waitSpan := lc.shutdownChildSpan(r, comp, SpanWait)
+	for childComp := range r.compToChildren[comp] {
+		if err := waitCtxErr(r.compStates[childComp].current().teardownCtx); err != nil {
+			waitSpan.end(err)
+			gen.cancelRun(err)
+			break
 		}
+	}
+	waitSpan.end(nil)
 
-		lc.log.Infof("Component %s [READY]", state.componentName)
-		return nil
-	})
+	if r.drainedCtx.Err() != nil {
+		gen.cancelRun(waitCtxErr(r.lifecycleCtx))
+	}
+	gen.cancelRun(nil)
+}
 
-	runner.Go(func() (runErr error) {
-		defer state.cancelTeardown(runErr)
-		<-startLatch
+// superviseShutdown traces the shutdown of the generation and gives up
+// waiting for it once its shutdown budget is spent.
+func (lc *lifecycle) superviseShutdown(r *runState, comp Component, gen *componentGeneration, task *trace.Task) {
+	state := r.compStates[comp]
+	defer close(gen.shutdownDone)
+	defer task.End()
+	<-gen.runCtx.Done()
+	defer r.traceStopped(comp, gen)
+
+	trace.Log(gen.traceCtx, "goscade", "stopping")
+	runPhase(gen.traceCtx, state.componentName, PhaseShutdown, func(context.Context) {
+		manualStop := r.lifecycleCtx.Err() == nil
+		timeout := lc.stopTimeout(state, manualStop)
+
+		var deadline <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			deadline = timer.C
+		}
 
-		for parentComp := range compToParents[comp] {
-			if err := waitProbeErr(compStates[parentComp].probeCtx); err != nil {
-				state.cancelProbe(err)
-				state.cancelRun(err)
-				return err
+		select {
+		case <-gen.teardownCtx.Done():
+		case <-deadline:
+			err := fmt.Errorf("not stopped after %s: %w", timeout, ShutdownTimeoutError)
+			timeoutErr := state.newError(comp, PhaseShutdown, err)
+			if manualStop {
+				gen.setErr(timeoutErr)
+			} else {
+				r.componentErrs.add(timeoutErr)
 			}
+			state.log.Error("component shutdown timed out",
+				LogKeyPhase, PhaseShutdown, LogKeyDuration, timeout, LogKeyError, err)
+			gen.cancelTeardown(timeoutErr)
+		}
+	})
+}
+
+// registerProbe makes Run's readiness wait for the generation to signal
+// ready or failed. A start timeout stops the lifecycle.
+func (lc *lifecycle) registerProbe(r *runState, comp Component, gen *componentGeneration) {
+	state := r.compStates[comp]
+	r.prober.Go(func() error {
+		err := lc.awaitReady(state, gen)
+		if err == nil || gen.probeCtx.Err() != nil {
+			return err
 		}
+		probeErr := state.newError(comp, PhaseStart, err)
+		r.componentErrs.add(probeErr)
+		r.startSpans[comp].end(probeErr)
+		state.log.Error("component not ready", LogKeyPhase, PhaseStart, LogKeyError, err)
+		r.lifecycleCtxCancel(probeErr)
+		return probeErr
+	})
+}
+
+// runGeneration waits for the dependencies of the generation once startLatch
+// is closed, runs the component and reports how its Run returned.
+func (lc *lifecycle) runGeneration(r *runState, comp Component, gen *componentGeneration, startLatch <-chan struct{}) {
+	state := r.compStates[comp]
+	defer gen.cancelTeardown(nil)
+	<-startLatch
+
+	gen.traceStart(r, comp)
+	state.log.Debug("component waiting for dependencies")
+	waitSpan := lc.traceWait(gen)
+	waitErr := lc.awaitParents(r, comp, gen)
+	waitSpan.end(waitErr)
+	if waitErr != nil {
+		gen.traceStarted(waitErr)
+		state.setStatus(ComponentStatusStopped)
+		gen.setErr(state.newError(comp, PhaseWaiting, waitErr))
+		gen.cancelProbe(waitErr)
+		gen.cancelRun(waitErr)
+		return
+	}
+
+	state.compareAndSetStatus(ComponentStatusWaiting, ComponentStatusStarting)
+	gen.startedAt.Store(time.Now().UnixNano())
+	state.log.Debug("component starting")
+	lc.traceRun(gen)
+	err := runRecovered(gen.traceCtx, comp, state.componentName, func(err error) {
+		gen.traceStarted(err)
+		lc.reportReadiness(r, comp, gen, err)
+	})
+	// End the startup spans if Run returned before the component was ready
+	notReadyErr := err
+	if notReadyErr == nil {
+		notReadyErr = UnexpectedCloseComponentError
+	}
+	gen.traceStarted(notReadyErr)
 
-		err := comp.Run(state.runCtx, func(err error) {
-			if err == nil {
-				state.cancelProbe(componentReady)
+	if _, ok := comp.(completer); ok && err == nil && gen.ready() {
+		state.setStatus(ComponentStatusCompleted)
+		state.log.Info("component completed")
+		return
+	}
+	state.reportStopped(lc.classifyRunResult(r, comp, gen, err))
+}
+
+// awaitParents waits until every dependency of the generation is ready. It
+// fails if a dependency fails or has already stopped without completing.
+func (lc *lifecycle) awaitParents(r *runState, comp Component, gen *componentGeneration) error {
+	var err error
+	runPhase(gen.traceCtx, r.compStates[comp].componentName, PhaseWaiting, func(context.Context) {
+		for parentComp := range r.compToParents[comp] {
+			parentState := r.compStates[parentComp]
+			parentGen := parentState.current()
+			if err = waitProbeErr(parentGen.probeCtx); err != nil {
 				return
 			}
-			probeErr := fmt.Errorf("component %s readiness: %w", state.componentName, err)
-			componentErrs.add(probeErr)
-			lifecycleCtxCancel(probeErr)
-			state.cancelProbe(probeErr)
-		})
-		if err == nil && lifecycleCtx.Err() == nil {
-			err = fmt.Errorf("component %s: %w", state.componentName, UnexpectedCloseComponentError)
-			componentErrs.add(err)
-			lifecycleCtxCancel(err)
-		} else if err != nil {
-			if independentErr := removePropagatedCancellation(err, state.runCtx); independentErr != nil {
-				if !errors.Is(context.Cause(lifecycleCtx), independentErr) {
-					componentErr := fmt.Errorf("component %s: %w", state.componentName, independentErr)
-					componentErrs.add(componentErr)
-					lifecycleCtxCancel(componentErr)
-				}
+			if parentGen.teardownCtx.Err() != nil && parentState.getStatus() != ComponentStatusCompleted {
+				err = fmt.Errorf("%w: %s", DependencyStoppedError, parentState.componentName)
+				return
 			}
 		}
+	})
+	return err
+}
 
-		switch {
-		case errors.Is(err, CascadeCloseComponentError):
-			lc.log.Infof("Component %s [CASCADE]", state.componentName)
-		case errors.Is(err, context.Canceled):
-			lc.log.Infof("Component %s [CLOSE]", state.componentName)
-		case errors.Is(err, nil):
-			lc.log.Infof("Component %s [CLOSE]", state.componentName)
-		default:
-			lc.log.Errorf("Component %s [ERROR] %v", state.componentName, err)
+// reportReadiness handles a call of the generation's readiness probe. A
+// readiness failure stops the lifecycle, unless the generation was started
+// manually while the lifecycle is running.
+func (lc *lifecycle) reportReadiness(r *runState, comp Component, gen *componentGeneration, err error) {
+	state := r.compStates[comp]
+	if err == nil {
+		state.compareAndSetStatus(ComponentStatusStarting, ComponentStatusReady)
+		gen.cancelProbe(componentReady)
+		return
+	}
+	state.setStatus(ComponentStatusFailed)
+	probeErr := state.newError(comp, PhaseReadiness, err)
+	if gen.manual && gen.probeCtx.Err() == nil {
+		gen.cancelProbe(probeErr)
+		return
+	}
+	r.componentErrs.add(probeErr)
+	r.lifecycleCtxCancel(probeErr)
+	gen.cancelProbe(probeErr)
+}
+
+// classifyRunResult reports the error returned by the component's Run, or its
+// unexpected return, to the lifecycle or to the caller of a manual operation.
+// It returns the error describing how the component stopped.
+func (lc *lifecycle) classifyRunResult(r *runState, comp Component, gen *componentGeneration, err error) error {
+	state := r.compStates[comp]
+	switch {
+	case r.lifecycleCtx.Err() == nil && gen.stopCtx.Err() != nil:
+		// Stopped by Stop, Restart or Reload: report to the caller only
+		if independentErr := removePropagatedCancellation(err, gen.runCtx); independentErr != nil {
+			gen.setErr(state.newError(comp, PhaseShutdown, independentErr))
 		}
+	case r.lifecycleCtx.Err() == nil && gen.manual && !gen.ready():
+		// Failed to become ready after Start, Restart or Reload
+		if err == nil {
+			err = UnexpectedCloseComponentError
+		}
+		err = state.newError(comp, PhaseRun, err)
+		gen.cancelProbe(err)
+	case err == nil && r.lifecycleCtx.Err() == nil:
+		err = state.newError(comp, PhaseRun, UnexpectedCloseComponentError)
+		r.componentErrs.add(err)
+		r.lifecycleCtxCancel(err)
+	case err != nil:
+		independentErr := removePropagatedCancellation(err, gen.runCtx)
+		if independentErr == nil || errors.Is(context.Cause(r.lifecycleCtx), independentErr) {
+			break
+		}
+		phase := PhaseRun
+		if gen.runCtx.Err() != nil {
+			phase = PhaseShutdown
+		}
+		componentErr := state.newError(comp, phase, independentErr)
+		r.componentErrs.add(componentErr)
+		r.lifecycleCtxCancel(componentErr)
+	}
+	return err
+}
 
-		return err
+// reportStopped sets the final status of the component and logs how it
+// stopped.
+func (s *componentState) reportStopped(err error) {
+	var panicErr *PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		s.setStatus(ComponentStatusFailed)
+		s.log.Error("component panicked",
+			LogKeyPhase, PhaseRun, LogKeyError, panicErr, "stack", string(panicErr.Stack))
+	case errors.Is(err, CascadeCloseComponentError):
+		s.setStatus(ComponentStatusStopped)
+		s.log.Info("component stopped in cascade")
+	case errors.Is(err, context.Canceled), err == nil:
+		s.setStatus(ComponentStatusStopped)
+		s.log.Info("component stopped")
+	default:
+		s.setStatus(ComponentStatusFailed)
+		s.log.Error("component failed", LogKeyError, err)
+	}
+}
+
+// awaitReady waits until the generation signals readiness. It returns the
+// probe failure, or an error wrapping context.DeadlineExceeded if the start
+// timeout expired first.
+func (lc *lifecycle) awaitReady(state *componentState, gen *componentGeneration) error {
+	probeCtx, cancel := context.WithTimeout(gen.probeCtx, state.startTimeout)
+	defer cancel()
+
+	var err error
+	runPhase(gen.traceCtx, state.componentName, PhaseStart, func(context.Context) {
+		err = waitProbeErr(probeCtx)
 	})
+	if err != nil {
+		if gen.probeCtx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("not ready after %s: %w", state.startTimeout, err)
+	}
+
+	trace.Log(gen.traceCtx, "goscade", "ready")
+	state.log.Info("component ready", LogKeyDuration, time.Since(time.Unix(0, gen.startedAt.Load())))
+	return nil
 }
 
 // Run starts all registered components and blocks until shutdown.
@@ -457,77 +1120,74 @@ func (lc *lifecycle) runComponent(
 // - Concurrent component startup
 // - Readiness probing and status management
 // - Error propagation and cascade shutdown
+// - Draining components implementing Drainer before the cascade
 // - Graceful shutdown on context cancellation
 //
 // The readinessProbe callback is called when all components are ready
 // or if there's an error during startup.
 // By default, the lifecycle will not respond to system signals unless
-// WithShutdownHook() option is used during lifecycle creation.
+// WithShutdownHook() or WithSignals() option is used during lifecycle creation.
+// A shutdown initiated by a signal has a *SignalError as its cause.
 // Run panics if no components have been registered.
+// A panic inside a component's Run is recovered, reported as a *PanicError
+// and handled like any other component failure.
+// If the shutdown timeout fires, the returned error contains a
+// *StuckComponentsError listing the components that have not stopped.
 // The returned error joins the shutdown cause with independent component errors.
 func (lc *lifecycle) Run(ctx context.Context, readinessProbe func(err error)) error {
 	if len(lc.components) == 0 {
 		panic("goscade: lifecycle has no components")
 	}
 
-	// Graceful shutdown on context cancellation or signal
-	if lc.shutdownHook {
-		var stop context.CancelFunc
-		ctx, stop = signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
-		defer stop()
-	}
 	lifecycleCtx, lifecycleCtxCancel := context.WithCancelCause(ctx)
 	defer lifecycleCtxCancel(context.Canceled)
-	compToParents := lc.buildCompToParents()
-	compToChildren := lc.buildCompToChildren(compToParents)
 
+	drainedCtx, cancelDrained := context.WithCancel(context.Background())
+	r := lc.newRunState(ctx, lifecycleCtx, lifecycleCtxCancel, drainedCtx)
+
+	// Graceful shutdown on context cancellation or signal
+	forceCtx, stopSignals := lc.watchSignals(r)
+	defer stopSignals()
 	if err := lc.writeGraphToFile(); err != nil {
-		lc.log.Errorf("Failed to write graph: %v", err)
+		lc.log.Error("failed to write graph", LogKeyError, err)
 	}
-	runner := &errgroup.Group{}
-	prober := &errgroup.Group{}
-	componentErrs := &componentErrors{}
+
+	startRoot := lc.traceStart(ctx, r)
 	startLatch := make(chan struct{})
-	compStates := make(map[Component]*componentState)
+	lc.mu.Lock()
+	lc.run = r
 	for comp := range lc.components {
-		state := &componentState{}
-		compStates[comp] = state
-		state.probeCtx, state.cancelProbe = context.WithCancelCause(lifecycleCtx)
-
-		state.runCtx, state.cancelRun = context.WithCancelCause(context.Background())
-
-		state.teardownCtx, state.cancelTeardown = context.WithCancelCause(context.Background())
-		state.componentName = lc.componentName(comp)
+		lc.startGeneration(r, comp, false, startLatch)
 	}
+	lc.mu.Unlock()
+	defer func() {
+		lc.mu.Lock()
+		lc.run = nil
+		lc.mu.Unlock()
+	}()
 
-	for comp := range lc.components {
-		lc.runComponent(
-			lifecycleCtx,
-			lifecycleCtxCancel,
-			comp,
-			runner,
-			prober,
-			compStates,
-			compToParents,
-			compToChildren,
-			startLatch,
-			componentErrs,
-		)
-	}
-
-	// Wait until all components are stopped
+	// Drain components once shutdown is requested, then start the cascade
 	go func() {
 		if err := waitCtxErr(lifecycleCtx); err != nil {
-			lc.log.Errorf("All components are stopping: %v", err)
+			lc.log.Error("lifecycle stopping", LogKeyError, err)
 		} else {
-			lc.log.Infof("All components are stopping")
+			lc.log.Info("lifecycle stopping")
 		}
+		lc.mu.Lock()
+		r.stopping = true
+		lc.mu.Unlock()
+
+		r.shutdownRoot = lc.traceShutdown(ctx, r)
+		lc.drain(r)
 		lc.setStatus(LifecycleStatusStopping)
+		r.shutdownDeadline = time.Now().Add(r.shutdownTimeout)
+		cancelDrained()
 	}()
 
 	// Wait until all probes are done (either ready or failed)
 	go func() {
-		probeErr := prober.Wait()
+		probeErr := r.prober.Wait()
+		startRoot.end(probeErr)
 		if probeErr == nil {
 			lc.setStatus(LifecycleStatusReady)
 		}
@@ -537,34 +1197,195 @@ func (lc *lifecycle) Run(ctx context.Context, readinessProbe func(err error)) er
 		}
 	}()
 
-	// Wait until all components are done
-	teardownCtx, cancelTeardown := context.WithCancelCause(context.Background())
+	// Wait until every component has stopped or exhausted its shutdown budget
+	teardownCtx, cancelTeardown := context.WithCancel(context.Background())
 	go func() {
-		err := runner.Wait()
-		if err != nil && !errors.Is(err, context.Canceled) {
-			lc.log.Errorf("All components are stopped: %v", err)
-		} else {
-			lc.log.Infof("All components are stopped")
+		<-drainedCtx.Done()
+		for _, state := range r.compStates {
+			<-state.current().shutdownDone
 		}
-
+		lc.log.Info("lifecycle stopped")
 		lc.setStatus(LifecycleStatusStopped)
-		cancelTeardown(err)
+		cancelTeardown()
 	}()
 
 	lc.setStatus(LifecycleStatusRunning)
 	close(startLatch)
 
-	<-lifecycleCtx.Done()
+	timeoutErr := lc.awaitShutdown(r, teardownCtx, forceCtx)
+
+	var leakErr error
+	if lc.leakCheck != "" && timeoutErr == nil {
+		if leaks := lc.goroutineLeakError(r.compStates); leaks != nil && lc.leakCheck == LeakCheckError {
+			leakErr = leaks
+		}
+	}
+
+	errs := append([]error{context.Cause(lifecycleCtx)}, r.componentErrs.snapshot()...)
+	return joinLifecycleErrors(append(errs, timeoutErr, leakErr)...)
+}
+
+// newRunState returns the state of a Run called with ctx: the dependency
+// graph, the base context of the components and the state of every
+// component.
+func (lc *lifecycle) newRunState(
+	ctx context.Context,
+	lifecycleCtx context.Context,
+	lifecycleCtxCancel context.CancelCauseFunc,
+	drainedCtx context.Context,
+) *runState {
+	compToParents := lc.buildCompToParents()
+	r := &runState{
+		lifecycleCtx:       lifecycleCtx,
+		lifecycleCtxCancel: lifecycleCtxCancel,
+		drainedCtx:         drainedCtx,
+		componentCtx:       context.Background(),
+		prober:             &errgroup.Group{},
+		compStates:         make(map[Component]*componentState),
+		compToParents:      compToParents,
+		compToChildren:     lc.buildCompToChildren(compToParents),
+		componentErrs:      &componentErrors{},
+		shutdownTimeout:    lc.shutdownTimeout,
+	}
+	if lc.propagateContext {
+		r.componentCtx = context.WithoutCancel(ctx)
+	}
+	for comp := range lc.components {
+		state := &componentState{
+			componentName: lc.componentName(comp),
+			log:           lc.log.With(LogKeyComponent, lc.componentName(comp)),
+			events:        &lc.events,
+		}
+		state.startTimeout, state.shutdownTimeout = lc.componentTimeouts(comp)
+		r.compStates[comp] = state
+	}
+	r.shutdownTimeout = max(r.shutdownTimeout, r.cascadeShutdownBudget())
+	return r
+}
+
+// cascadeShutdownBudget returns the sum of the component shutdown budgets
+// along the longest child-first chain: a component is asked to stop only once
+// its children have stopped, so the budgets of a chain add up.
+func (r *runState) cascadeShutdownBudget() time.Duration {
+	budgets := make(map[Component]time.Duration, len(r.compStates))
+	var budget func(comp Component) time.Duration
+	budget = func(comp Component) time.Duration {
+		if b, ok := budgets[comp]; ok {
+			return b
+		}
+		var children time.Duration
+		for child := range r.compToChildren[comp] {
+			children = max(children, budget(child))
+		}
+		budgets[comp] = r.compStates[comp].shutdownTimeout + children
+		return budgets[comp]
+	}
+
+	var longest time.Duration
+	for comp := range r.compStates {
+		longest = max(longest, budget(comp))
+	}
+	return longest
+}
+
+// awaitShutdown waits until shutdown has been requested and every component
+// has stopped. It returns a *StuckComponentsError if the shutdown timeout
+// expires first, or the cause of forceCtx if the shutdown is forced.
+func (lc *lifecycle) awaitShutdown(r *runState, teardownCtx, forceCtx context.Context) error {
+	<-r.lifecycleCtx.Done()
+	select {
+	case <-r.drainedCtx.Done():
+	case <-forceCtx.Done():
+	}
 
 	var timeoutErr error
-	timer := time.NewTimer(lc.shutdownTimeout)
+	timer := time.NewTimer(r.shutdownTimeout)
 	defer timer.Stop()
 	select {
 	case <-teardownCtx.Done():
+	case <-forceCtx.Done():
+		timeoutErr = context.Cause(forceCtx)
 	case <-timer.C:
-		timeoutErr = ShutdownTimeoutError
+		timeoutErr = lc.stuckComponentsError(r.shutdownTimeout, r.compStates)
+	}
+
+	if r.drainedCtx.Err() != nil {
+		r.endShutdownTrace(timeoutErr)
 	}
+	return timeoutErr
+}
 
-	errs := append([]error{context.Cause(lifecycleCtx)}, componentErrs.snapshot()...)
-	return joinLifecycleErrors(append(errs, timeoutErr)...)
+// goroutineLeakError waits up to leakCheckGracePeriod for goroutines started by
+// components to exit and reports the ones still running, or returns nil.
+func (lc *lifecycle) goroutineLeakError(compStates map[Component]*componentState) *LeakedGoroutinesError {
+	deadline := time.Now().Add(leakCheckGracePeriod)
+	for {
+		leakErr := &LeakedGoroutinesError{}
+		goroutines := componentGoroutines()
+		for comp, state := range compStates {
+			if profile, ok := goroutines[state.componentName]; ok {
+				leakErr.Leaks = append(leakErr.Leaks, GoroutineLeak{
+					Component:  comp,
+					Name:       state.componentName,
+					Count:      profile.count,
+					Goroutines: profile.stacks,
+				})
+			}
+		}
+		if len(leakErr.Leaks) == 0 {
+			return nil
+		}
+
+		if time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		sort.Slice(leakErr.Leaks, func(i, j int) bool {
+			return leakErr.Leaks[i].Name < leakErr.Leaks[j].Name
+		})
+		for _, leak := range leakErr.Leaks {
+			lc.log.Warn("component leaked goroutines",
+				LogKeyComponent, leak.Name, "count", leak.Count, "goroutines", leak.Goroutines)
+		}
+		return leakErr
+	}
+}
+
+// stuckComponentsError reports every component whose teardown is still pending.
+func (lc *lifecycle) stuckComponentsError(
+	timeout time.Duration,
+	compStates map[Component]*componentState,
+) *StuckComponentsError {
+	now := time.Now()
+	var goroutines map[string]goroutineProfile
+	if lc.goroutineDump {
+		goroutines = componentGoroutines()
+	}
+	stuckErr := &StuckComponentsError{Timeout: timeout}
+	for comp, state := range compStates {
+		gen := state.current()
+		if gen.teardownCtx.Err() != nil {
+			continue
+		}
+
+		stuck := StuckComponent{Component: comp, Name: state.componentName}
+		if at := gen.stopRequestedAt.Load(); at != 0 {
+			stuck.Stopping = now.Sub(time.Unix(0, at))
+		}
+		stuck.Goroutines = goroutines[state.componentName].stacks
+		stuckErr.Components = append(stuckErr.Components, stuck)
+	}
+	sort.Slice(stuckErr.Components, func(i, j int) bool {
+		return stuckErr.Components[i].Name < stuckErr.Components[j].Name
+	})
+
+	for _, stuck := range stuckErr.Components {
+		attrs := []any{LogKeyComponent, stuck.Name, LogKeyDuration, stuck.Stopping}
+		if stuck.Goroutines != "" {
+			attrs = append(attrs, "goroutines", stuck.Goroutines)
+		}
+		lc.log.Error("component stuck", attrs...)
+	}
+	return stuckErr
 }
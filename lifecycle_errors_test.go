@@ -175,3 +175,302 @@ func TestLifecycle_JoinsShutdownTimeoutWithPrimaryCause(t *testing.T) {
 	assert.ErrorIs(t, runErr, primaryErr)
 	assert.ErrorIs(t, runErr, ShutdownTimeoutError)
 }
+
+func TestLifecycle_ReportsStuckComponents(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	lc := NewLifecycle(&mockLogger{}, WithShutdownTimeout(50*time.Millisecond), WithGoroutineDump())
+	database := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}}
+	api := &lifecycleErrorComponent{name: "api", run: func(_ context.Context, probe func(error)) error {
+		go blockUntilReleased(release)
+		probe(nil)
+		<-release
+		return nil
+	}}
+	lc.Register(database)
+	lc.Register(api, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, ShutdownTimeoutError)
+	var stuckErr *StuckComponentsError
+	require.ErrorAs(t, runErr, &stuckErr)
+	assert.Equal(t, 50*time.Millisecond, stuckErr.Timeout)
+	require.Len(t, stuckErr.Components, 2)
+
+	stuckAPI := stuckErr.Components[0]
+	assert.Equal(t, "api", stuckAPI.Name)
+	assert.Same(t, api, stuckAPI.Component)
+	assert.GreaterOrEqual(t, stuckAPI.Stopping, 50*time.Millisecond)
+	assert.Contains(t, stuckAPI.Goroutines, "blockUntilReleased")
+
+	stuckDatabase := stuckErr.Components[1]
+	assert.Equal(t, "database", stuckDatabase.Name)
+	assert.Zero(t, stuckDatabase.Stopping)
+	assert.NotContains(t, stuckDatabase.Goroutines, "blockUntilReleased")
+
+	assert.Regexp(t, `^shutdown timeout after 50ms: api stopping for \d+ms, database waiting for dependents$`, stuckErr.Error())
+}
+
+func blockUntilReleased(release <-chan struct{}) {
+	<-release
+}
+
+func TestLifecycle_RecoversComponentPanic(t *testing.T) {
+	fail := make(chan struct{})
+	stopped := make(chan struct{})
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&lifecycleErrorComponent{name: "database", run: func(_ context.Context, probe func(error)) error {
+		probe(nil)
+		<-fail
+		panic("connection pool corrupted")
+	}})
+	lc.Register(&lifecycleErrorComponent{name: "api", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}})
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	close(fail)
+
+	runErr := receiveLifecycleError(t, done)
+	var panicErr *PanicError
+	require.ErrorAs(t, runErr, &panicErr)
+	assert.Equal(t, "database", panicErr.Name)
+	assert.Equal(t, "connection pool corrupted", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "lifecycle_errors_test.go")
+	assert.EqualError(t, runErr, "component database: panic: connection pool corrupted")
+	<-stopped
+}
+
+func TestLifecycle_PanicErrorUnwrapsErrorValue(t *testing.T) {
+	panicValue := errors.New("nil map write")
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&lifecycleErrorComponent{name: "cache", run: func(_ context.Context, _ func(error)) error {
+		panic(panicValue)
+	}})
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	assert.Error(t, receiveLifecycleError(t, ready))
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, panicValue)
+	var panicErr *PanicError
+	require.ErrorAs(t, runErr, &panicErr)
+	assert.Equal(t, "cache", panicErr.Name)
+}
+
+func TestRecover(t *testing.T) {
+	comp := &lifecycleErrorComponent{name: "worker"}
+	assert.NoError(t, Recover(context.Background(), comp, func() error { return nil }))
+
+	err := Recover(context.Background(), comp, func() error { panic("job failed") })
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Same(t, comp, panicErr.Component)
+	assert.Equal(t, "job failed", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "lifecycle_errors_test.go")
+}
+
+func TestLifecycle_ComponentErrorPhases(t *testing.T) {
+	readinessErr := errors.New("migrations pending")
+	cleanupErr := errors.New("flush failed")
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(readinessErr)
+		<-ctx.Done()
+		return nil
+	}})
+	lc.Register(&lifecycleErrorComponent{name: "cache", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return cleanupErr
+	}})
+	before := time.Now()
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	assert.ErrorIs(t, receiveLifecycleError(t, ready), readinessErr)
+	runErr := receiveLifecycleError(t, done)
+
+	phases := make(map[string]*ComponentError)
+	for _, compErr := range ComponentErrors(runErr) {
+		phases[compErr.Name] = compErr
+	}
+	require.Contains(t, phases, "database")
+	assert.Equal(t, PhaseReadiness, phases["database"].Phase)
+	assert.ErrorIs(t, phases["database"], readinessErr)
+	assert.False(t, phases["database"].Time.Before(before))
+	assert.EqualError(t, phases["database"], "component database readiness: migrations pending")
+
+	require.Contains(t, phases, "cache")
+	assert.Equal(t, PhaseShutdown, phases["cache"].Phase)
+	assert.EqualError(t, phases["cache"], "component cache shutdown: flush failed")
+
+	var compErr *ComponentError
+	require.ErrorAs(t, runErr, &compErr)
+	assert.Equal(t, "database", compErr.Name)
+	assert.NotNil(t, compErr.Component)
+}
+
+func TestLifecycle_ComponentErrorStartTimeout(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{}, WithStartTimeout(20*time.Millisecond))
+	lc.Register(&lifecycleErrorComponent{name: "slow", run: func(ctx context.Context, _ func(error)) error {
+		<-ctx.Done()
+		return nil
+	}})
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	assert.ErrorIs(t, receiveLifecycleError(t, ready), context.DeadlineExceeded)
+
+	compErrs := ComponentErrors(receiveLifecycleError(t, done))
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseStart, compErrs[0].Phase)
+	assert.EqualError(t, compErrs[0], "component slow start: not ready after 20ms: context deadline exceeded")
+}
+
+type timeoutComponent struct {
+	lifecycleErrorComponent
+	start    time.Duration
+	shutdown time.Duration
+}
+
+func (c *timeoutComponent) StartTimeout() time.Duration {
+	return c.start
+}
+
+func (c *timeoutComponent) ShutdownTimeout() time.Duration {
+	return c.shutdown
+}
+
+func TestLifecycle_PerComponentStartTimeout(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{}, WithStartTimeout(20*time.Millisecond))
+	lc.Register(&timeoutComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "migrator", run: func(ctx context.Context, probe func(error)) error {
+			time.Sleep(50 * time.Millisecond)
+			probe(nil)
+			<-ctx.Done()
+			return nil
+		}},
+		start: time.Second,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestLifecycle_PerComponentStartTimeoutNamesComponent(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&timeoutComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "cache", run: func(ctx context.Context, _ func(error)) error {
+			<-ctx.Done()
+			return nil
+		}},
+		start: 20 * time.Millisecond,
+	})
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	assert.ErrorIs(t, receiveLifecycleError(t, ready), context.DeadlineExceeded)
+
+	compErrs := ComponentErrors(receiveLifecycleError(t, done))
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseStart, compErrs[0].Phase)
+	assert.EqualError(t, compErrs[0], "component cache start: not ready after 20ms: context deadline exceeded")
+}
+
+func TestLifecycle_PerComponentShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	databaseStopped := make(chan struct{})
+	lc := NewLifecycle(&mockLogger{})
+	database := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		close(databaseStopped)
+		return nil
+	}}
+	api := &timeoutComponent{
+		lifecycleErrorComponent: lifecycleErrorComponent{name: "api", run: func(_ context.Context, probe func(error)) error {
+			probe(nil)
+			<-release
+			return nil
+		}},
+		shutdown: 30 * time.Millisecond,
+	}
+	lc.Register(database)
+	lc.Register(api, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, ShutdownTimeoutError)
+	compErrs := ComponentErrors(runErr)
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, "api", compErrs[0].Name)
+	assert.Equal(t, PhaseShutdown, compErrs[0].Phase)
+	assert.EqualError(t, compErrs[0], "component api shutdown: not stopped after 30ms: shutdown timeout")
+	<-databaseStopped
+}
+
+func TestLifecycle_ShutdownTimeoutCoversChainedBudgets(t *testing.T) {
+	slowStop := func(name string) *timeoutComponent {
+		return &timeoutComponent{
+			lifecycleErrorComponent: lifecycleErrorComponent{name: name, run: func(ctx context.Context, probe func(error)) error {
+				probe(nil)
+				<-ctx.Done()
+				time.Sleep(70 * time.Millisecond)
+				return nil
+			}},
+			shutdown: 100 * time.Millisecond,
+		}
+	}
+	lc := NewLifecycle(&mockLogger{}, WithShutdownTimeout(10*time.Millisecond))
+	database := slowStop("database")
+	api := slowStop("api")
+	lc.Register(database)
+	lc.Register(api, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+
+	runErr := receiveLifecycleError(t, done)
+	assert.NotErrorIs(t, runErr, ShutdownTimeoutError)
+	assert.Empty(t, ComponentErrors(runErr))
+}
+
+func TestAdapter_ForwardsComponentTimeouts(t *testing.T) {
+	comp := NewAdapter(&timeoutComponent{start: time.Second, shutdown: 2 * time.Second},
+		func(context.Context, *timeoutComponent, func(error)) error { return nil })
+
+	assert.Equal(t, time.Second, comp.(StartTimeoutProvider).StartTimeout())
+	assert.Equal(t, 2*time.Second, comp.(ShutdownTimeoutProvider).ShutdownTimeout())
+	assert.Zero(t, NewAdapter(&componentA{}, nil).(StartTimeoutProvider).StartTimeout())
+}
+
+func TestComponentErrors(t *testing.T) {
+	first := newComponentError(nil, "a", PhaseRun, errors.New("a failed"))
+	second := newComponentError(nil, "b", PhaseShutdown, errors.New("b failed"))
+	err := fmt.Errorf("wrapped: %w", errors.Join(first, errors.New("other"), second))
+
+	assert.Equal(t, []*ComponentError{first, second}, ComponentErrors(err))
+	assert.Empty(t, ComponentErrors(nil))
+	assert.Empty(t, ComponentErrors(errors.New("plain")))
+	assert.EqualError(t, first, "component a: a failed")
+}
@@ -0,0 +1,224 @@
+package goscade
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of the attributes attached to the records the lifecycle logs.
+const (
+	// LogKeyLifecycle is the ID of the lifecycle, attached to every record.
+	LogKeyLifecycle = "lifecycle_id"
+	// LogKeyComponent is the name of the component a record is about.
+	LogKeyComponent = "component"
+	// LogKeyPhase is the Phase in which a component failed.
+	LogKeyPhase = "phase"
+	// LogKeyDuration is how long an operation took or is allowed to take.
+	LogKeyDuration = "duration"
+	// LogKeyError is the error being reported.
+	LogKeyError = "error"
+)
+
+// PrintfLogger is a printf-style logger. NewLifecycle wraps it with
+// NewPrintfHandler.
+type PrintfLogger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// WithLogger makes the lifecycle write structured records to log instead of
+// the PrintfLogger passed to NewLifecycle.
+func WithLogger(log *slog.Logger) Option {
+	return func(lc *lifecycle) {
+		lc.log = log
+	}
+}
+
+// WithLogHandler makes the lifecycle write structured records to handler
+// instead of the PrintfLogger passed to NewLifecycle.
+func WithLogHandler(handler slog.Handler) Option {
+	return WithLogger(slog.New(handler))
+}
+
+// newLifecycleID returns a random identifier for a lifecycle.
+func newLifecycleID() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// printfHandler is a slog.Handler writing records to a PrintfLogger.
+type printfHandler struct {
+	log   PrintfLogger
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+// NewPrintfHandler returns a slog.Handler that writes each record to log as
+// a single line: the message followed by its attributes as key=value pairs.
+// Multi-line values such as stack traces are appended after the line.
+// Records at slog.LevelWarn and above are written with Errorf, the others
+// with Infof. Only opts.Level is used; by default Debug records are dropped.
+//
+// The records the lifecycle logged before it switched to log/slog, such as
+// components becoming ready or stopping, keep their printf format, e.g.
+// "Component api [READY]".
+func NewPrintfHandler(log PrintfLogger, opts *slog.HandlerOptions) slog.Handler {
+	h := &printfHandler{log: log, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+func (h *printfHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *printfHandler) Handle(_ context.Context, record slog.Record) error {
+	if format, args, ok := h.legacyFormat(record); ok {
+		if record.Level >= slog.LevelWarn {
+			h.log.Errorf(format, args...)
+		} else {
+			h.log.Infof(format, args...)
+		}
+		return nil
+	}
+
+	var line, trailer strings.Builder
+	line.WriteString(record.Message)
+	for _, attr := range h.attrs {
+		appendAttr(&line, &trailer, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&line, &trailer, h.group, attr)
+		return true
+	})
+	line.WriteString(trailer.String())
+
+	if record.Level >= slog.LevelWarn {
+		h.log.Errorf("%s", line.String())
+	} else {
+		h.log.Infof("%s", line.String())
+	}
+	return nil
+}
+
+// legacyFormat describes how a record was formatted before the lifecycle
+// switched to log/slog. Records without an error use format, the others
+// errFormat; the component name comes first if component is set.
+type legacyFormat struct {
+	format    string
+	errFormat string
+	component bool
+}
+
+// legacyFormats are the legacy formats by record message.
+var legacyFormats = map[string]legacyFormat{
+	"component ready":              {format: "Component %s [READY]", component: true},
+	"component stopped":            {format: "Component %s [CLOSE]", component: true},
+	"component stopped in cascade": {format: "Component %s [CASCADE]", component: true},
+	"component failed":             {errFormat: "Component %s [ERROR] %v", component: true},
+	"component not ready":          {errFormat: "Component %s [PROB ERROR]: %v", component: true},
+	"lifecycle stopping":           {format: "All components are stopping", errFormat: "All components are stopping: %v"},
+	"lifecycle stopped":            {format: "All components are stopped"},
+	"failed to write graph":        {errFormat: "Failed to write graph: %v"},
+}
+
+// legacyFormat returns the legacy format of record and its arguments, or
+// false if record has none.
+func (h *printfHandler) legacyFormat(record slog.Record) (string, []any, bool) {
+	legacy, ok := legacyFormats[record.Message]
+	if !ok || h.group != "" {
+		return "", nil, false
+	}
+
+	var comp, err any
+	find := func(attr slog.Attr) bool {
+		switch attr.Key {
+		case LogKeyComponent:
+			comp = attr.Value.Resolve().Any()
+		case LogKeyError:
+			err = attr.Value.Resolve().Any()
+		}
+		return true
+	}
+	for _, attr := range h.attrs {
+		find(attr)
+	}
+	record.Attrs(find)
+
+	var args []any
+	if legacy.component {
+		if comp == nil {
+			return "", nil, false
+		}
+		args = append(args, comp)
+	}
+	switch {
+	case err != nil && legacy.errFormat != "":
+		return legacy.errFormat, append(args, err), true
+	case err == nil && legacy.format != "":
+		return legacy.format, args, true
+	}
+	return "", nil, false
+}
+
+func (h *printfHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, h.attrs...)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + attr.Key
+		}
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+func (h *printfHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
+
+// appendAttr writes attr to line as key=value, or to trailer if its value
+// spans several lines.
+func appendAttr(line, trailer *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, child := range attr.Value.Group() {
+			appendAttr(line, trailer, prefix, child)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if strings.Contains(value, "\n") {
+		fmt.Fprintf(trailer, "\n%s%s:\n%s", prefix, attr.Key, strings.TrimRight(value, "\n"))
+		return
+	}
+	if value == "" || strings.ContainsAny(value, " \"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(line, " %s%s=%s", prefix, attr.Key, value)
+}
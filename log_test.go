@@ -0,0 +1,149 @@
+package goscade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+// jsonRecords decodes the records written to buf by a slog.JSONHandler.
+func jsonRecords(t *testing.T, buf *testutil.SyncBuffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	dec := json.NewDecoder(strings.NewReader(buf.String()))
+	for dec.More() {
+		var record map[string]any
+		require.NoError(t, dec.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestPrintfHandler_FormatsRecords(t *testing.T) {
+	log := &testutil.RecordingLogger{}
+	logger := slog.New(NewPrintfHandler(log, nil)).With(LogKeyLifecycle, "abc")
+
+	logger.Info("component reloaded", LogKeyComponent, "api", LogKeyDuration, 1500*time.Millisecond)
+	logger.WithGroup("http").Info("listening", "addr", ":8080", "note", "two words", "empty", "")
+	logger.Info("component panicked", LogKeyError, errors.New("boom"), "stack", "line 1\nline 2\n")
+
+	assert.Equal(t, []string{
+		"component reloaded lifecycle_id=abc component=api duration=1.5s",
+		`listening lifecycle_id=abc http.addr=:8080 http.note="two words" http.empty=""`,
+		"component panicked lifecycle_id=abc error=boom\nstack:\nline 1\nline 2",
+	}, log.Infos())
+	assert.Empty(t, log.Errors())
+}
+
+func TestPrintfHandler_KeepsLegacyFormats(t *testing.T) {
+	log := &testutil.RecordingLogger{}
+	logger := slog.New(NewPrintfHandler(log, nil)).With(LogKeyLifecycle, "abc")
+	api := logger.With(LogKeyComponent, "api")
+
+	api.Info("component ready", LogKeyDuration, time.Second)
+	api.Info("component stopped in cascade")
+	api.Info("component stopped")
+	api.Error("component failed", LogKeyError, errors.New("boom"))
+	logger.Info("lifecycle stopping")
+	logger.Error("lifecycle stopping", LogKeyError, errors.New("boom"))
+	logger.Error("failed to write graph", LogKeyError, errors.New("read-only"))
+
+	assert.Equal(t, []string{
+		"Component api [READY]",
+		"Component api [CASCADE]",
+		"Component api [CLOSE]",
+		"All components are stopping",
+	}, log.Infos())
+	assert.Equal(t, []string{
+		"Component api [ERROR] boom",
+		"All components are stopping: boom",
+		"Failed to write graph: read-only",
+	}, log.Errors())
+}
+
+func TestPrintfHandler_Levels(t *testing.T) {
+	log := &testutil.RecordingLogger{}
+	logger := slog.New(NewPrintfHandler(log, nil))
+	logger.Debug("dropped")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+	assert.Equal(t, []string{"info"}, log.Infos())
+	assert.Equal(t, []string{"warn", "error"}, log.Errors())
+
+	log = &testutil.RecordingLogger{}
+	logger = slog.New(NewPrintfHandler(log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger.Debug("debug")
+	assert.Equal(t, []string{"debug"}, log.Infos())
+}
+
+func TestLifecycle_WithLogHandler(t *testing.T) {
+	var buf testutil.SyncBuffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	lc := NewLifecycle(nil, WithLogHandler(handler))
+	runErr := errors.New("connection lost")
+	lc.Register(&lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		return runErr
+	}})
+
+	err := lc.Run(context.Background(), nil)
+	require.ErrorIs(t, err, runErr)
+
+	records := jsonRecords(t, &buf)
+	require.NotEmpty(t, records)
+	id := records[0][LogKeyLifecycle]
+	require.NotEmpty(t, id)
+	byMsg := make(map[string]map[string]any)
+	for _, record := range records {
+		assert.Equal(t, id, record[LogKeyLifecycle])
+		byMsg[record["msg"].(string)] = record
+	}
+
+	require.Contains(t, byMsg, "component starting")
+	assert.Equal(t, "DEBUG", byMsg["component starting"]["level"])
+	require.Contains(t, byMsg, "component ready")
+	assert.Equal(t, "database", byMsg["component ready"][LogKeyComponent])
+	assert.Contains(t, byMsg["component ready"], LogKeyDuration)
+	require.Contains(t, byMsg, "lifecycle stopping")
+	assert.Equal(t, "ERROR", byMsg["lifecycle stopping"]["level"])
+	assert.Contains(t, byMsg["lifecycle stopping"][LogKeyError], "connection lost")
+}
+
+func TestLifecycle_WithLoggerAddsPhase(t *testing.T) {
+	var buf testutil.SyncBuffer
+	lc := NewLifecycle(nil, WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))), WithStartTimeout(10*time.Millisecond))
+	lc.Register(&lifecycleErrorComponent{name: "slow", run: func(ctx context.Context, probe func(error)) error {
+		<-ctx.Done()
+		return nil
+	}})
+
+	require.Error(t, lc.Run(context.Background(), nil))
+
+	var notReady map[string]any
+	for _, record := range jsonRecords(t, &buf) {
+		if record["msg"] == "component not ready" {
+			notReady = record
+		}
+	}
+	require.NotNil(t, notReady)
+	assert.Equal(t, "slow", notReady[LogKeyComponent])
+	assert.Equal(t, string(PhaseStart), notReady[LogKeyPhase])
+	assert.Contains(t, notReady[LogKeyError], "not ready after 10ms")
+}
+
+func TestLifecycle_DistinctIDs(t *testing.T) {
+	first := NewLifecycle(nil).(*lifecycle)
+	second := NewLifecycle(nil).(*lifecycle)
+	assert.Len(t, first.id, 16)
+	assert.NotEqual(t, first.id, second.id)
+}
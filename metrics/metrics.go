@@ -0,0 +1,358 @@
+// Package metrics collects component lifecycle metrics from goscade events
+// and serves them in the Prometheus text exposition format, without depending
+// on the Prometheus client library.
+//
+// The collector is fed either synchronously through goscade.WithEventHandler,
+// which never drops events, or from the event stream with Watch:
+//
+//	collector := metrics.NewCollector()
+//	lc := goscade.NewLifecycle(log, goscade.WithEventHandler(collector.Observe))
+//	http.Handle("/metrics", collector)
+//
+// The following metrics are exported:
+//
+//	goscade_lifecycle_status{status}                      1 for the current lifecycle status, 0 otherwise
+//	goscade_component_status{component,status}            1 for the current component status, 0 otherwise
+//	goscade_component_startup_duration_seconds{component} histogram of the time from Run to readiness
+//	goscade_component_readiness_latency_seconds{component} time from the last start, including waiting for dependencies, to readiness
+//	goscade_component_restarts_total{component}           number of times the component was started again within a Run
+//	goscade_component_shutdown_duration_seconds{component} time the component took to stop the last time
+//	goscade_component_errors_total{component,phase}       number of errors reported by the component
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the startup duration
+// histogram buckets.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var lifecycleStatuses = []goscade.LifecycleStatus{
+	goscade.LifecycleStatusIdle,
+	goscade.LifecycleStatusRunning,
+	goscade.LifecycleStatusReady,
+	goscade.LifecycleStatusDraining,
+	goscade.LifecycleStatusStopping,
+	goscade.LifecycleStatusStopped,
+}
+
+var componentStatuses = []goscade.ComponentStatus{
+	goscade.ComponentStatusWaiting,
+	goscade.ComponentStatusStarting,
+	goscade.ComponentStatusReady,
+	goscade.ComponentStatusCompleted,
+	goscade.ComponentStatusDraining,
+	goscade.ComponentStatusStopping,
+	goscade.ComponentStatusStopped,
+	goscade.ComponentStatusFailed,
+}
+
+// Option configures a Collector.
+type Option func(*Collector)
+
+// WithBuckets sets the upper bounds, in seconds, of the startup duration
+// histogram buckets. The default is DefaultBuckets.
+func WithBuckets(buckets ...float64) Option {
+	return func(c *Collector) {
+		c.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(c.buckets)
+	}
+}
+
+// Collector aggregates lifecycle events into metrics. It implements
+// http.Handler serving the metrics in the Prometheus text format.
+type Collector struct {
+	buckets []float64
+
+	mu              sync.Mutex
+	lifecycleStatus goscade.LifecycleStatus
+	components      map[string]*componentMetrics
+}
+
+// componentMetrics holds the metrics of a single component.
+type componentMetrics struct {
+	status     goscade.ComponentStatus
+	waitingAt  time.Time
+	startingAt time.Time
+	stoppingAt time.Time
+	// started is set once the component has been started by the current Run.
+	started  bool
+	restarts uint64
+
+	startupCounts    []uint64
+	startupCount     uint64
+	startupSum       float64
+	readinessLatency float64
+	shutdownDuration float64
+	errors           map[goscade.Phase]uint64
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		buckets:         DefaultBuckets,
+		lifecycleStatus: goscade.LifecycleStatusIdle,
+		components:      make(map[string]*componentMetrics),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Watch feeds the collector from lc's event stream until ctx is done. It
+// records the current status of lc before returning and handles events in a
+// background goroutine. Events dropped by the stream are not counted, so
+// prefer goscade.WithEventHandler(c.Observe) where possible.
+func (c *Collector) Watch(ctx context.Context, lc goscade.Lifecycle) {
+	events := lc.Subscribe(ctx)
+	snapshot := lc.Snapshot()
+
+	c.mu.Lock()
+	c.lifecycleStatus = snapshot.Status
+	for _, comp := range snapshot.Components {
+		if comp.Status != "" {
+			metrics := c.component(comp.Name)
+			metrics.status = comp.Status
+			metrics.started = snapshot.Status != goscade.LifecycleStatusStopped
+		}
+	}
+	c.mu.Unlock()
+
+	go func() {
+		for event := range events {
+			c.Observe(event)
+		}
+	}()
+}
+
+// Observe records a single event.
+func (c *Collector) Observe(event goscade.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if event.Component == "" {
+		if event.LifecycleStatus != "" {
+			c.lifecycleStatus = event.LifecycleStatus
+		}
+		// The next Run starts every component for the first time again
+		if event.LifecycleStatus == goscade.LifecycleStatusStopped {
+			for _, comp := range c.components {
+				comp.started = false
+			}
+		}
+		return
+	}
+
+	comp := c.component(event.Component)
+	if event.Err != nil {
+		comp.errors[event.Err.Phase]++
+		return
+	}
+
+	switch event.ComponentStatus {
+	case goscade.ComponentStatusWaiting:
+		if comp.started {
+			comp.restarts++
+		}
+		comp.started = true
+		comp.waitingAt = event.Time
+	case goscade.ComponentStatusStarting:
+		comp.startingAt = event.Time
+	case goscade.ComponentStatusReady:
+		if !comp.startingAt.IsZero() {
+			c.observeStartup(comp, event.Time.Sub(comp.startingAt).Seconds())
+		}
+		if !comp.waitingAt.IsZero() {
+			comp.readinessLatency = event.Time.Sub(comp.waitingAt).Seconds()
+		}
+	case goscade.ComponentStatusStopping:
+		comp.stoppingAt = event.Time
+	case goscade.ComponentStatusStopped, goscade.ComponentStatusFailed:
+		if !comp.stoppingAt.IsZero() {
+			comp.shutdownDuration = event.Time.Sub(comp.stoppingAt).Seconds()
+			comp.stoppingAt = time.Time{}
+		}
+	}
+	if event.ComponentStatus != "" {
+		comp.status = event.ComponentStatus
+	}
+}
+
+// component returns the metrics of the named component, creating them if
+// needed. The caller must hold c.mu.
+func (c *Collector) component(name string) *componentMetrics {
+	comp, ok := c.components[name]
+	if !ok {
+		comp = &componentMetrics{
+			startupCounts: make([]uint64, len(c.buckets)),
+			errors:        make(map[goscade.Phase]uint64),
+		}
+		c.components[name] = comp
+	}
+	return comp
+}
+
+// observeStartup adds a startup duration to the histogram of comp.
+func (c *Collector) observeStartup(comp *componentMetrics, seconds float64) {
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			comp.startupCounts[i]++
+		}
+	}
+	comp.startupCount++
+	comp.startupSum += seconds
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.components))
+	for name := range c.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	p := &printer{w: bw}
+
+	p.header("goscade_lifecycle_status", "gauge", "Current status of the lifecycle.")
+	for _, status := range lifecycleStatuses {
+		p.sample("goscade_lifecycle_status", boolValue(c.lifecycleStatus == status), "status", string(status))
+	}
+
+	p.header("goscade_component_status", "gauge", "Current status of the component.")
+	for _, name := range names {
+		for _, status := range componentStatuses {
+			p.sample("goscade_component_status", boolValue(c.components[name].status == status),
+				"component", name, "status", string(status))
+		}
+	}
+
+	p.header("goscade_component_startup_duration_seconds", "histogram", "Time from running the component to its readiness.")
+	for _, name := range names {
+		comp := c.components[name]
+		for i, bound := range c.buckets {
+			p.sample("goscade_component_startup_duration_seconds_bucket", float64(comp.startupCounts[i]),
+				"component", name, "le", formatFloat(bound))
+		}
+		p.sample("goscade_component_startup_duration_seconds_bucket", float64(comp.startupCount), "component", name, "le", "+Inf")
+		p.sample("goscade_component_startup_duration_seconds_sum", comp.startupSum, "component", name)
+		p.sample("goscade_component_startup_duration_seconds_count", float64(comp.startupCount), "component", name)
+	}
+
+	p.header("goscade_component_readiness_latency_seconds", "gauge", "Time from the last start of the component to its readiness.")
+	for _, name := range names {
+		p.sample("goscade_component_readiness_latency_seconds", c.components[name].readinessLatency, "component", name)
+	}
+
+	p.header("goscade_component_restarts_total", "counter", "Number of times the component was started again.")
+	for _, name := range names {
+		p.sample("goscade_component_restarts_total", float64(c.components[name].restarts), "component", name)
+	}
+
+	p.header("goscade_component_shutdown_duration_seconds", "gauge", "Time the component took to stop the last time.")
+	for _, name := range names {
+		p.sample("goscade_component_shutdown_duration_seconds", c.components[name].shutdownDuration, "component", name)
+	}
+
+	p.header("goscade_component_errors_total", "counter", "Number of errors reported by the component, by phase.")
+	for _, name := range names {
+		comp := c.components[name]
+		phases := make([]string, 0, len(comp.errors))
+		for phase := range comp.errors {
+			phases = append(phases, string(phase))
+		}
+		sort.Strings(phases)
+		for _, phase := range phases {
+			p.sample("goscade_component_errors_total", float64(comp.errors[goscade.Phase(phase)]),
+				"component", name, "phase", phase)
+		}
+	}
+
+	if p.err == nil {
+		p.err = bw.Flush()
+	}
+	return cw.n, p.err
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// printer writes metric lines and keeps the first write error.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) header(name, typ, help string) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+}
+
+// sample writes a sample with labels given as name/value pairs.
+func (p *printer) sample(name string, value float64, labels ...string) {
+	if p.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, labels[i], labelValueEscaper.Replace(labels[i+1]))
+	}
+	if len(labels) > 0 {
+		b.WriteByte('}')
+	}
+	_, p.err = fmt.Fprintf(p.w, "%s %s\n", b.String(), formatFloat(value))
+}
+
+// labelValueEscaper escapes label values as required by the text format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
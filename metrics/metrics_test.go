@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+type database struct{}
+
+func (*database) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+func TestCollector_Observe(t *testing.T) {
+	start := time.Unix(0, 0)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	c := NewCollector(WithBuckets(1, 0.1))
+	for _, event := range []goscade.Event{
+		{Time: at(0), LifecycleStatus: goscade.LifecycleStatusRunning},
+		{Time: at(0), Component: "db", ComponentStatus: goscade.ComponentStatusWaiting},
+		{Time: at(time.Second), Component: "db", ComponentStatus: goscade.ComponentStatusStarting},
+		{Time: at(1500 * time.Millisecond), Component: "db", ComponentStatus: goscade.ComponentStatusReady},
+		{Time: at(2 * time.Second), Component: "db", ComponentStatus: goscade.ComponentStatusStopping},
+		{Time: at(2250 * time.Millisecond), Component: "db", ComponentStatus: goscade.ComponentStatusStopped},
+		{Time: at(3 * time.Second), Component: "db", ComponentStatus: goscade.ComponentStatusWaiting},
+		{Time: at(3 * time.Second), Component: "db", ComponentStatus: goscade.ComponentStatusStarting},
+		{Time: at(3050 * time.Millisecond), Component: "db", ComponentStatus: goscade.ComponentStatusReady},
+		{Time: at(4 * time.Second), Component: "db", Err: &goscade.ComponentError{Phase: goscade.PhaseRun, Err: errors.New("lost")}},
+		{Time: at(4 * time.Second), Component: "db", ComponentStatus: goscade.ComponentStatusFailed},
+		{Time: at(4 * time.Second), LifecycleStatus: goscade.LifecycleStatusStopping},
+	} {
+		c.Observe(event)
+	}
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+	assert.Equal(t, `# HELP goscade_lifecycle_status Current status of the lifecycle.
+# TYPE goscade_lifecycle_status gauge
+goscade_lifecycle_status{status="idle"} 0
+goscade_lifecycle_status{status="running"} 0
+goscade_lifecycle_status{status="ready"} 0
+goscade_lifecycle_status{status="draining"} 0
+goscade_lifecycle_status{status="stopping"} 1
+goscade_lifecycle_status{status="stopped"} 0
+# HELP goscade_component_status Current status of the component.
+# TYPE goscade_component_status gauge
+goscade_component_status{component="db",status="waiting"} 0
+goscade_component_status{component="db",status="starting"} 0
+goscade_component_status{component="db",status="ready"} 0
+goscade_component_status{component="db",status="completed"} 0
+goscade_component_status{component="db",status="draining"} 0
+goscade_component_status{component="db",status="stopping"} 0
+goscade_component_status{component="db",status="stopped"} 0
+goscade_component_status{component="db",status="failed"} 1
+# HELP goscade_component_startup_duration_seconds Time from running the component to its readiness.
+# TYPE goscade_component_startup_duration_seconds histogram
+goscade_component_startup_duration_seconds_bucket{component="db",le="0.1"} 1
+goscade_component_startup_duration_seconds_bucket{component="db",le="1"} 2
+goscade_component_startup_duration_seconds_bucket{component="db",le="+Inf"} 2
+goscade_component_startup_duration_seconds_sum{component="db"} 0.55
+goscade_component_startup_duration_seconds_count{component="db"} 2
+# HELP goscade_component_readiness_latency_seconds Time from the last start of the component to its readiness.
+# TYPE goscade_component_readiness_latency_seconds gauge
+goscade_component_readiness_latency_seconds{component="db"} 0.05
+# HELP goscade_component_restarts_total Number of times the component was started again.
+# TYPE goscade_component_restarts_total counter
+goscade_component_restarts_total{component="db"} 1
+# HELP goscade_component_shutdown_duration_seconds Time the component took to stop the last time.
+# TYPE goscade_component_shutdown_duration_seconds gauge
+goscade_component_shutdown_duration_seconds{component="db"} 0.25
+# HELP goscade_component_errors_total Number of errors reported by the component, by phase.
+# TYPE goscade_component_errors_total counter
+goscade_component_errors_total{component="db",phase="run"} 1
+`, buf.String())
+}
+
+func TestCollector_SecondRunIsNotARestart(t *testing.T) {
+	c := NewCollector()
+	for _, event := range []goscade.Event{
+		{LifecycleStatus: goscade.LifecycleStatusRunning},
+		{Component: "db", ComponentStatus: goscade.ComponentStatusWaiting},
+		{Component: "db", ComponentStatus: goscade.ComponentStatusStopped},
+		{LifecycleStatus: goscade.LifecycleStatusStopped},
+		{Component: "db", ComponentStatus: goscade.ComponentStatusWaiting},
+		{LifecycleStatus: goscade.LifecycleStatusRunning},
+	} {
+		c.Observe(event)
+	}
+
+	var buf strings.Builder
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `goscade_component_restarts_total{component="db"} 0`)
+
+	c.Observe(goscade.Event{Component: "db", ComponentStatus: goscade.ComponentStatusWaiting})
+	buf.Reset()
+	_, err = c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `goscade_component_restarts_total{component="db"} 1`)
+}
+
+func TestCollector_EscapesLabelValues(t *testing.T) {
+	c := NewCollector()
+	c.Observe(goscade.Event{Component: "a\"b\\c\nd", ComponentStatus: goscade.ComponentStatusWaiting})
+
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `goscade_component_status{component="a\"b\\c\nd",status="waiting"} 1`)
+}
+
+func TestCollector_WithEventHandler(t *testing.T) {
+	c := NewCollector()
+	lc := goscade.NewLifecycle(testutil.NopLogger{}, goscade.WithEventHandler(c.Observe))
+	lc.Register(&database{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() { done <- lc.Run(ctx, func(err error) { ready <- err }) }()
+	require.NoError(t, <-ready)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `goscade_lifecycle_status{status="ready"} 1`)
+	assert.Contains(t, rec.Body.String(), `goscade_component_status{component="*metrics.database",status="ready"} 1`)
+	assert.Contains(t, rec.Body.String(), `goscade_component_startup_duration_seconds_count{component="*metrics.database"} 1`)
+
+	cancel()
+	<-done
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `goscade_component_status{component="*metrics.database",status="stopped"} 1`)
+	assert.Contains(t, rec.Body.String(), `goscade_lifecycle_status{status="stopped"} 1`)
+}
+
+func TestCollector_Watch(t *testing.T) {
+	c := NewCollector()
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(&database{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Watch(ctx, lc)
+	ready := make(chan error, 1)
+	go func() { _ = lc.Run(ctx, func(err error) { ready <- err }) }()
+	require.NoError(t, <-ready)
+
+	require.Eventually(t, func() bool {
+		var buf strings.Builder
+		_, _ = c.WriteTo(&buf)
+		return strings.Contains(buf.String(), `goscade_lifecycle_status{status="ready"} 1`)
+	}, time.Second, time.Millisecond)
+}
+
+// cache appends to its own fields until it is stopped.
+type cache struct {
+	db      *database
+	entries []int
+}
+
+func (c *cache) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	for i := 0; ctx.Err() == nil; i++ {
+		c.entries = append(c.entries[:0], i)
+		time.Sleep(10 * time.Microsecond)
+	}
+	return nil
+}
+
+func TestCollector_WatchWhileComponentsMutateFields(t *testing.T) {
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(&cache{db: &database{}})
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	ctx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	for i := 0; i < 10; i++ {
+		NewCollector().Watch(ctx, lc)
+	}
+
+	c := NewCollector()
+	c.Watch(ctx, lc)
+	var buf strings.Builder
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `goscade_component_status{component="*metrics.cache",status="ready"} 1`)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,101 @@
+package goscade
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ComponentLabel is the pprof label key holding the component name. It is
+	// set on the goroutine running a component's Run method and inherited by
+	// the goroutines it starts, so CPU profiles, goroutine dumps and execution
+	// traces can be filtered by component.
+	ComponentLabel = "goscade.component"
+
+	// PhaseLabel is the pprof label key holding the Phase the labeled goroutine
+	// is serving: "waiting" while a component waits for its dependencies,
+	// "start" while the lifecycle waits for its readiness probe, "run" inside
+	// its Run method, "drain" inside its Drain method and "shutdown" while the
+	// lifecycle waits for it to stop.
+	PhaseLabel = "goscade.phase"
+
+	// traceTaskType is the runtime/trace task type created for every component.
+	traceTaskType = "goscade.component"
+)
+
+// startComponentTrace creates the runtime/trace task that spans a component's
+// whole lifecycle. The task is tagged with the component name.
+func startComponentTrace(ctx context.Context, name string) (context.Context, *trace.Task) {
+	ctx, task := trace.NewTask(ctx, traceTaskType)
+	trace.Log(ctx, ComponentLabel, name)
+	return ctx, task
+}
+
+// runPhase calls fn inside a runtime/trace region named after phase, with the
+// component and phase pprof labels applied to the calling goroutine. The
+// context passed to fn carries phase; see CurrentPhase.
+func runPhase(ctx context.Context, name string, phase Phase, fn func(ctx context.Context)) {
+	pprof.Do(withPhase(ctx, phase), pprof.Labels(ComponentLabel, name, PhaseLabel, string(phase)), func(ctx context.Context) {
+		trace.WithRegion(ctx, "goscade."+string(phase), func() {
+			fn(ctx)
+		})
+	})
+}
+
+// goroutineProfile holds the goroutines attributed to a single component.
+type goroutineProfile struct {
+	// count is the number of goroutines.
+	count int
+	// stacks are the goroutine profile entries in the text format of the
+	// goroutine profile at debug level 1.
+	stacks string
+}
+
+// componentGoroutines returns the goroutines running a component's Run method
+// or started by it, keyed by component name. Goroutines the lifecycle itself
+// uses to track components carry a different phase label and are excluded.
+func componentGoroutines() map[string]goroutineProfile {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	componentKey := fmt.Sprintf("%q:", ComponentLabel)
+	phaseLabel := fmt.Sprintf("%q:%q", PhaseLabel, PhaseRun)
+	profiles := make(map[string]goroutineProfile)
+	for _, block := range strings.Split(buf.String(), "\n\n") {
+		block = strings.TrimSpace(block)
+		for _, line := range strings.Split(block, "\n") {
+			labels, ok := strings.CutPrefix(line, "# labels: ")
+			if !ok || !strings.Contains(labels, phaseLabel) {
+				continue
+			}
+
+			_, value, ok := strings.Cut(labels, componentKey)
+			if !ok {
+				break
+			}
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				break
+			}
+			name, _ := strconv.Unquote(quoted)
+			count, _ := strconv.Atoi(strings.Fields(block)[0])
+
+			profile := profiles[name]
+			profile.count += count
+			if profile.stacks != "" {
+				profile.stacks += "\n\n"
+			}
+			profile.stacks += block
+			profiles[name] = profile
+			break
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,148 @@
+package goscade
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+func TestRunPhase_SetsComponentLabels(t *testing.T) {
+	labels := make(chan map[string]string, 1)
+	lc := NewLifecycle(&mockLogger{})
+	lc.Register(&lifecycleErrorComponent{name: "worker", run: func(ctx context.Context, probe func(error)) error {
+		component, _ := pprof.Label(ctx, ComponentLabel)
+		phase, _ := pprof.Label(ctx, PhaseLabel)
+		labels <- map[string]string{ComponentLabel: component, PhaseLabel: phase}
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	receiveLifecycleError(t, done)
+
+	assert.Equal(t, map[string]string{ComponentLabel: "worker", PhaseLabel: "run"}, <-labels)
+}
+
+func TestComponentGoroutines(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	runPhase(context.Background(), "profiled", PhaseRun, func(context.Context) {
+		go func() {
+			close(started)
+			blockUntilReleased(release)
+		}()
+	})
+	runPhase(context.Background(), "profiled", PhaseShutdown, func(context.Context) {
+		go blockUntilReleased(release)
+	})
+	defer close(release)
+	<-started
+
+	profiles := componentGoroutines()
+	require.Contains(t, profiles, "profiled")
+	assert.Equal(t, 1, profiles["profiled"].count)
+	assert.Contains(t, profiles["profiled"].stacks, "blockUntilReleased")
+	assert.Contains(t, profiles["profiled"].stacks, `"goscade.phase":"run"`)
+	assert.NotContains(t, profiles["profiled"].stacks, `"goscade.phase":"shutdown"`)
+}
+
+func TestLifecycle_TracesComponentPhases(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("execution tracer is already running")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+	lc := NewLifecycle(&mockLogger{})
+	database := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}}
+	lc.Register(database)
+	lc.Register(&lifecycleErrorComponent{name: "api", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}}, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	receiveLifecycleError(t, done)
+	trace.Stop()
+
+	for _, name := range []string{"goscade.component", "goscade.waiting", "goscade.start", "goscade.run", "goscade.shutdown"} {
+		assert.Contains(t, buf.String(), name)
+	}
+}
+
+func runLeakingLifecycle(t *testing.T, lc Lifecycle, release chan struct{}) error {
+	t.Helper()
+	lc.Register(&lifecycleErrorComponent{name: "leaky", run: func(ctx context.Context, probe func(error)) error {
+		go blockUntilReleased(release)
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}})
+	lc.Register(&lifecycleErrorComponent{name: "tidy", run: func(ctx context.Context, probe func(error)) error {
+		go func() {
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+		}()
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	cancel()
+	return receiveLifecycleError(t, done)
+}
+
+func TestLifecycle_LeakCheckError(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	lc := NewLifecycle(&mockLogger{}, WithLeakCheck(LeakCheckError))
+
+	runErr := runLeakingLifecycle(t, lc, release)
+	assert.ErrorIs(t, runErr, GoroutineLeakError)
+	var leakErr *LeakedGoroutinesError
+	require.ErrorAs(t, runErr, &leakErr)
+	require.Len(t, leakErr.Leaks, 1)
+	assert.Equal(t, "leaky", leakErr.Leaks[0].Name)
+	assert.Equal(t, 1, leakErr.Leaks[0].Count)
+	assert.Contains(t, leakErr.Leaks[0].Goroutines, "blockUntilReleased")
+	assert.EqualError(t, leakErr, "goroutine leak: leaky leaked 1 goroutines")
+}
+
+func TestLifecycle_LeakCheckLog(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	log := &testutil.RecordingLogger{}
+	lc := NewLifecycle(log, WithLeakCheck(LeakCheckLog))
+
+	runErr := runLeakingLifecycle(t, lc, release)
+	assert.NotErrorIs(t, runErr, GoroutineLeakError)
+	errs := log.Errors()
+	require.Len(t, errs, 1)
+	assert.True(t, strings.HasPrefix(errs[0], "component leaked goroutines "))
+	assert.Contains(t, errs[0], " component=leaky count=1")
+	assert.Contains(t, errs[0], "blockUntilReleased")
+}
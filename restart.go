@@ -0,0 +1,305 @@
+package goscade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Reloader can be implemented by a component that re-reads its configuration
+// when it is reloaded. Reload is called by Lifecycle.Reload after the component
+// has stopped and before it is started again. Adapters are looked through, so
+// a wrapped value implementing Reloader is reloaded like a component.
+type Reloader interface {
+	// Reload applies new configuration to the stopped component.
+	Reload(ctx context.Context) error
+}
+
+// Reload stops comps and every component that depends on them, child-first,
+// while the rest of the graph keeps running. Once they have stopped, it calls
+// the hook set by WithReloadHook and then Reload on each stopped component
+// that implements Reloader, parent-first. Finally it starts the stopped
+// components again, parent-first, and waits until they are ready.
+// The components are started again even if reloading fails.
+// Reload returns LifecycleNotReadyError unless the lifecycle is ready.
+func (lc *lifecycle) Reload(comps ...Component) error {
+	lc.opMu.Lock()
+	defer lc.opMu.Unlock()
+
+	r, err := lc.activeRun(comps)
+	if err != nil {
+		return err
+	}
+
+	stopped, stopErr := lc.stopComponents(r, comps)
+	reloadErr := lc.reloadComponents(r, stopped)
+	startErr := lc.startComponents(r, stopped)
+	return joinLifecycleErrors(stopErr, reloadErr, startErr)
+}
+
+// Stop stops comp and every component depending on it, child-first, without
+// stopping the rest of the graph, and waits until they have stopped. Errors
+// returned by the stopped components are wrapped in *ComponentError.
+// Stop returns LifecycleNotReadyError unless the lifecycle is ready.
+func (lc *lifecycle) Stop(comp Component) error {
+	lc.opMu.Lock()
+	defer lc.opMu.Unlock()
+
+	r, err := lc.activeRun([]Component{comp})
+	if err != nil {
+		return err
+	}
+
+	_, err = lc.stopComponents(r, []Component{comp})
+	return err
+}
+
+// Start starts comp and every stopped component it depends on, parent-first,
+// and waits until they are ready. Components depending on comp are not started.
+// If any of them fails to start, all of them are stopped again.
+// Start returns LifecycleNotReadyError unless the lifecycle is ready.
+func (lc *lifecycle) Start(comp Component) error {
+	lc.opMu.Lock()
+	defer lc.opMu.Unlock()
+
+	r, err := lc.activeRun([]Component{comp})
+	if err != nil {
+		return err
+	}
+
+	var comps []Component
+	for ancestor := range closure([]Component{comp}, r.compToParents) {
+		comps = append(comps, ancestor)
+	}
+	return lc.startComponents(r, comps)
+}
+
+// Restart stops comp and every component depending on it like Stop, then
+// starts the same components again like Start.
+func (lc *lifecycle) Restart(comp Component) error {
+	lc.opMu.Lock()
+	defer lc.opMu.Unlock()
+
+	r, err := lc.activeRun([]Component{comp})
+	if err != nil {
+		return err
+	}
+
+	stopped, stopErr := lc.stopComponents(r, []Component{comp})
+	startErr := lc.startComponents(r, stopped)
+	return joinLifecycleErrors(stopErr, startErr)
+}
+
+// reloadOnSignal reloads every component implementing Reloader.
+func (lc *lifecycle) reloadOnSignal() {
+	var comps []Component
+	for comp := range lc.components {
+		if _, ok := asOptional[Reloader](comp); ok {
+			comps = append(comps, comp)
+		}
+	}
+
+	if err := lc.Reload(comps...); err != nil {
+		lc.log.Error("reload failed", LogKeyError, err)
+	}
+}
+
+// activeRun returns the state of the active Run call, or an error if the
+// lifecycle is not ready or one of comps is not registered.
+func (lc *lifecycle) activeRun(comps []Component) (*runState, error) {
+	lc.mu.RLock()
+	r, status := lc.run, lc.status
+	lc.mu.RUnlock()
+	if r == nil || status != LifecycleStatusReady {
+		return nil, LifecycleNotReadyError
+	}
+
+	for _, comp := range comps {
+		if _, ok := r.compStates[comp]; !ok {
+			return nil, fmt.Errorf("%w: %s", UnregisteredComponentError, lc.componentName(comp))
+		}
+	}
+	return r, nil
+}
+
+// stopComponents stops roots and all their transitive children that are
+// running or completed, child-first, and waits until they have stopped. It
+// returns the components it stopped.
+func (lc *lifecycle) stopComponents(r *runState, roots []Component) ([]Component, error) {
+	var (
+		stopped []Component
+		gens    []*componentGeneration
+	)
+	for comp := range closure(roots, r.compToChildren) {
+		state := r.compStates[comp]
+		if gen := state.current(); gen.teardownCtx.Err() == nil || state.getStatus() == ComponentStatusCompleted {
+			stopped = append(stopped, comp)
+			gens = append(gens, gen)
+		}
+	}
+
+	for _, gen := range gens {
+		gen.requestStop()
+	}
+
+	errs := make([]error, 0, len(gens))
+	for i, gen := range gens {
+		<-gen.teardownCtx.Done()
+		r.compStates[stopped[i]].compareAndSetStatus(ComponentStatusCompleted, ComponentStatusStopped)
+		errs = append(errs, gen.getErr())
+	}
+	return stopped, joinLifecycleErrors(errs...)
+}
+
+// reloadComponents calls the reload hook and then Reload on every component
+// in comps implementing Reloader, parent-first.
+func (lc *lifecycle) reloadComponents(r *runState, comps []Component) error {
+	if lc.reloadHook != nil {
+		lc.reloadHook()
+	}
+
+	var errs []error
+	for _, comp := range parentFirst(comps, r.compToParents) {
+		reloader, ok := asOptional[Reloader](comp)
+		if !ok {
+			continue
+		}
+
+		state := r.compStates[comp]
+		ctx := withPhase(lc.componentContext(r.lifecycleCtx, state), PhaseReload)
+		if err := reloader.Reload(ctx); err != nil {
+			state.log.Error("component reload failed", LogKeyPhase, PhaseReload, LogKeyError, err)
+			errs = append(errs, state.newError(comp, PhaseReload, err))
+			continue
+		}
+		state.log.Info("component reloaded")
+	}
+	return errors.Join(errs...)
+}
+
+// startComponents starts every stopped component in comps and waits until all
+// of them are ready. Completed components are not started again. Components
+// wait for the components they depend on, so they effectively start
+// parent-first. If any of them fails to start, all of them are stopped again.
+func (lc *lifecycle) startComponents(r *runState, comps []Component) error {
+	lc.mu.Lock()
+	if r.stopping {
+		lc.mu.Unlock()
+		return LifecycleNotReadyError
+	}
+	startLatch := make(chan struct{})
+	gens := make(map[Component]*componentGeneration)
+	for _, comp := range comps {
+		if state := r.compStates[comp]; state.current().teardownCtx.Err() != nil && state.getStatus() != ComponentStatusCompleted {
+			gens[comp] = lc.startGeneration(r, comp, true, startLatch)
+		}
+	}
+	lc.mu.Unlock()
+	close(startLatch)
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	for comp, gen := range gens {
+		wg.Add(1)
+		go func(comp Component, gen *componentGeneration) {
+			defer wg.Done()
+			state := r.compStates[comp]
+			err := lc.awaitReady(state, gen)
+			if err == nil {
+				return
+			}
+			if gen.probeCtx.Err() == nil {
+				state.log.Error("component not ready", LogKeyPhase, PhaseStart, LogKeyError, err)
+				err = state.newError(comp, PhaseStart, err)
+				gen.cancelProbe(err)
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}(comp, gen)
+	}
+	wg.Wait()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	started := make([]Component, 0, len(gens))
+	for comp := range gens {
+		started = append(started, comp)
+	}
+	_, stopErr := lc.stopComponents(r, started)
+	return joinLifecycleErrors(append(errs, stopErr)...)
+}
+
+// closure returns roots together with every component reachable from them
+// by following edges.
+func closure(roots []Component, edges map[Component]map[Component]struct{}) map[Component]struct{} {
+	visited := make(map[Component]struct{})
+	queue := &fifoQueue[Component]{}
+	for _, root := range roots {
+		queue.Push(root)
+	}
+
+	for !queue.IsEmpty() {
+		comp, _ := queue.Pop()
+		if _, ok := visited[comp]; ok {
+			continue
+		}
+		visited[comp] = struct{}{}
+		for next := range edges[comp] {
+			queue.Push(next)
+		}
+	}
+	return visited
+}
+
+// parentFirst orders comps so that every component comes after the components
+// in comps it depends on. Components on a dependency cycle are appended last.
+func parentFirst(comps []Component, compToParents map[Component]map[Component]struct{}) []Component {
+	pending := make(map[Component]int, len(comps))
+	for _, comp := range comps {
+		pending[comp] = 0
+	}
+	for _, comp := range comps {
+		for parent := range compToParents[comp] {
+			if _, ok := pending[parent]; ok {
+				pending[comp]++
+			}
+		}
+	}
+
+	ordered := make([]Component, 0, len(comps))
+	for len(pending) > 0 {
+		var wave []Component
+		for _, comp := range comps {
+			if count, ok := pending[comp]; ok && count == 0 {
+				wave = append(wave, comp)
+			}
+		}
+		if len(wave) == 0 {
+			for _, comp := range comps {
+				if _, ok := pending[comp]; ok {
+					ordered = append(ordered, comp)
+				}
+			}
+			break
+		}
+
+		for _, comp := range wave {
+			delete(pending, comp)
+			ordered = append(ordered, comp)
+		}
+		for comp := range pending {
+			for _, parent := range wave {
+				if _, ok := compToParents[comp][parent]; ok {
+					pending[comp]--
+				}
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,252 @@
+package goscade
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadingComponent struct {
+	lifecycleErrorComponent
+	reload func(context.Context) error
+}
+
+func (c *reloadingComponent) Reload(ctx context.Context) error {
+	return c.reload(ctx)
+}
+
+// recordedComponent records when it starts and stops.
+func recordedComponent(name string, events *eventRecorder) *lifecycleErrorComponent {
+	return &lifecycleErrorComponent{name: name, run: func(ctx context.Context, probe func(error)) error {
+		events.record(name + " started")
+		probe(nil)
+		<-ctx.Done()
+		events.record(name + " stopped")
+		return nil
+	}}
+}
+
+func TestLifecycle_ReloadRestartsAffectedSubgraph(t *testing.T) {
+	events := &eventRecorder{}
+	var hookCalls atomic.Int32
+	lc := NewLifecycle(&mockLogger{}, WithReloadHook(func() {
+		hookCalls.Add(1)
+		events.record("hook")
+	}))
+	database := &reloadingComponent{
+		lifecycleErrorComponent: *recordedComponent("database", events),
+		reload: func(context.Context) error {
+			events.record("database reloaded")
+			return nil
+		},
+	}
+	api := recordedComponent("api", events)
+	metrics := recordedComponent("metrics", events)
+	lc.Register(database)
+	lc.Register(api, database)
+	lc.Register(metrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.Len(t, events.snapshot(), 3)
+
+	require.NoError(t, lc.Reload(database))
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+	assert.Equal(t, []string{
+		"api stopped",
+		"database stopped",
+		"hook",
+		"database reloaded",
+		"database started",
+		"api started",
+	}, events.snapshot()[3:])
+	assert.EqualValues(t, 1, hookCalls.Load())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.Contains(t, events.snapshot(), "metrics stopped")
+}
+
+func TestLifecycle_ReloadErrors(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{})
+	comp := recordedComponent("database", &eventRecorder{})
+	lc.Register(comp)
+	assert.ErrorIs(t, lc.Reload(comp), LifecycleNotReadyError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	err := lc.Reload(&componentA{})
+	assert.ErrorIs(t, err, UnregisteredComponentError)
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.ErrorIs(t, lc.Reload(comp), LifecycleNotReadyError)
+}
+
+func TestLifecycle_ReloadReportsReloaderError(t *testing.T) {
+	reloadErr := errors.New("invalid config")
+	lc := NewLifecycle(&mockLogger{})
+	comp := &reloadingComponent{
+		lifecycleErrorComponent: *recordedComponent("database", &eventRecorder{}),
+		reload:                  func(context.Context) error { return reloadErr },
+	}
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	err := lc.Reload(comp)
+	assert.ErrorIs(t, err, reloadErr)
+	compErrs := ComponentErrors(err)
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseReload, compErrs[0].Phase)
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestAdapter_ReloadsDelegate(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{})
+	comp := NewAdapter(&reloadingComponent{reload: func(context.Context) error {
+		events.record("reloaded")
+		return nil
+	}}, func(ctx context.Context, _ *reloadingComponent, probe func(error)) error {
+		events.record("started")
+		probe(nil)
+		<-ctx.Done()
+		return nil
+	})
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	require.NoError(t, lc.Reload(comp))
+	assert.Equal(t, []string{"started", "reloaded", "started"}, events.snapshot())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestLifecycle_ReloadStopsComponentsThatFailToStart(t *testing.T) {
+	var runs atomic.Int32
+	lc := NewLifecycle(&mockLogger{}, WithStartTimeout(20*time.Millisecond))
+	comp := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		if runs.Add(1) == 1 {
+			probe(nil)
+		}
+		<-ctx.Done()
+		return nil
+	}}
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	err := lc.Reload(comp)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	compErrs := ComponentErrors(err)
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseStart, compErrs[0].Phase)
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestLifecycle_StopAndStartComponent(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{})
+	database := recordedComponent("database", events)
+	api := recordedComponent("api", events)
+	metrics := recordedComponent("metrics", events)
+	lc.Register(database)
+	lc.Register(api, database)
+	lc.Register(metrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	require.Len(t, events.snapshot(), 3)
+
+	require.NoError(t, lc.Stop(database))
+	assert.Equal(t, []string{"api stopped", "database stopped"}, events.snapshot()[3:])
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+	require.NoError(t, lc.Stop(database))
+
+	require.NoError(t, lc.Start(api))
+	assert.Equal(t, []string{"database started", "api started"}, events.snapshot()[5:])
+
+	require.NoError(t, lc.Restart(database))
+	assert.Equal(t, []string{
+		"api stopped",
+		"database stopped",
+		"database started",
+		"api started",
+	}, events.snapshot()[7:])
+	assert.NotContains(t, events.snapshot(), "metrics stopped")
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
+
+func TestLifecycle_StartLeavesDependentsStopped(t *testing.T) {
+	events := &eventRecorder{}
+	lc := NewLifecycle(&mockLogger{})
+	database := recordedComponent("database", events)
+	api := recordedComponent("api", events)
+	lc.Register(database)
+	lc.Register(api, database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	require.NoError(t, lc.Stop(database))
+	require.NoError(t, lc.Start(database))
+	assert.Equal(t, "database started", events.snapshot()[len(events.snapshot())-1])
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+	assert.Equal(t, "database stopped", events.snapshot()[len(events.snapshot())-1])
+}
+
+func TestLifecycle_StopReportsComponentError(t *testing.T) {
+	stopErr := errors.New("flush failed")
+	lc := NewLifecycle(&mockLogger{})
+	comp := &lifecycleErrorComponent{name: "database", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return stopErr
+	}}
+	lc.Register(comp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+
+	err := lc.Stop(comp)
+	assert.ErrorIs(t, err, stopErr)
+	compErrs := ComponentErrors(err)
+	require.Len(t, compErrs, 1)
+	assert.Equal(t, PhaseShutdown, compErrs[0].Phase)
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
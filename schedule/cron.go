@@ -0,0 +1,209 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs next.
+type Schedule interface {
+	// Next returns the first activation time after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule activating every interval.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("schedule: non-positive interval")
+	}
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule activates at the minutes matching all of its fields. Each
+// field is a bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// anyDay is set if either the day of month or the day of week is "*",
+	// in which case both must match. Otherwise either of them may match.
+	anyDay bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{min: 0, max: 59}
+	hourField   = cronField{min: 0, max: 23}
+	domField    = cronField{min: 1, max: 31}
+	monthField  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron parses a cron expression with five fields: minute, hour, day of
+// month, month and day of week. Fields accept "*", values, ranges ("1-5"),
+// steps ("*/15", "0-30/10") and lists ("1,15"); months and days of week also
+// accept three-letter English names. Sunday is 0 or 7. As in cron, a job
+// runs on a day matching either the day of month or the day of week if both
+// are restricted. The descriptors @yearly, @monthly, @weekly, @daily and
+// @hourly, and "@every <duration>", are accepted too. Times are evaluated in
+// the location of the time passed to Next.
+func Cron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if interval, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("schedule: invalid interval in %q", expr)
+		}
+		return every(d), nil
+	}
+	if spec, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = spec
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: expected 5 fields in %q, got %d", expr, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	for i, p := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&s.minute, minuteField},
+		{&s.hour, hourField},
+		{&s.dom, domField},
+		{&s.month, monthField},
+		{&s.dow, dowField},
+	} {
+		if *p.bits, err = p.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("schedule: %q: %w", expr, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	// As in cron, a field starting with "*", such as "*/2", is unrestricted
+	s.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+// MustCron is like Cron but panics if expr cannot be parsed.
+func MustCron(expr string) Schedule {
+	s, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parse returns the bitset of the values matched by a comma-separated list.
+func (f cronField) parse(list string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(list, ",") {
+		rng, step, hasStep := strings.Cut(part, "/")
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(first); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(last); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+
+		n := 1
+		if hasStep {
+			var err error
+			if n, err = strconv.Atoi(step); err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+		for v := lo; v <= hi; v += n {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value or name of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first matching minute after t. It gives up after five
+// years, which only happens for dates that do not exist, such as "0 0 30 2 *".
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
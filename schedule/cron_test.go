@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2025, time.January, 15, 10, 17, 30, 0, time.UTC)
+	for expr, want := range map[string]time.Time{
+		"* * * * *":            time.Date(2025, time.January, 15, 10, 18, 0, 0, time.UTC),
+		"*/15 * * * *":         time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC),
+		"0 * * * *":            time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		"30 9 * * *":           time.Date(2025, time.January, 16, 9, 30, 0, 0, time.UTC),
+		"0 0 1 * *":            time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * * mon-fri":     time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC),
+		"0 12 * * sat,sun":     time.Date(2025, time.January, 18, 12, 0, 0, 0, time.UTC),
+		"0 0 * * 7":            time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC),
+		"0 0 25 * 1":           time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC),
+		"0 0 */2 * 1":          time.Date(2025, time.January, 27, 0, 0, 0, 0, time.UTC),
+		"0 0 29 feb *":         time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"5-20/5 10 * * *":      time.Date(2025, time.January, 15, 10, 20, 0, 0, time.UTC),
+		"10,40 22 * jun-aug *": time.Date(2025, time.June, 1, 22, 10, 0, 0, time.UTC),
+		"@hourly":              time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		"@daily":               time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC),
+		"@weekly":              time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC),
+		"@yearly":              time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"@every 90s":           time.Date(2025, time.January, 15, 10, 19, 0, 0, time.UTC),
+	} {
+		s, err := Cron(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, s.Next(from), expr)
+	}
+}
+
+func TestCron_NeverMatches(t *testing.T) {
+	s := MustCron("0 0 30 2 *")
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every",
+		"@every -1s",
+	} {
+		_, err := Cron(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustCron("bad") })
+	assert.Panics(t, func() { Every(0) })
+}
+
+func TestEvery_Next(t *testing.T) {
+	from := time.Date(2025, time.January, 15, 10, 17, 30, 0, time.UTC)
+	assert.Equal(t, from.Add(time.Minute), Every(time.Minute).Next(from))
+}
@@ -0,0 +1,174 @@
+// Package schedule provides a goscade component that runs a job on an
+// interval or a cron schedule.
+//
+// A job is an ordinary component, so it starts once the components it
+// depends on are ready and stops before them:
+//
+//	job := schedule.New(schedule.MustCron("*/5 * * * *"), func(ctx context.Context) error {
+//		return store.Compact(ctx)
+//	}, schedule.WithJitter(30*time.Second))
+//	lc.Register(job, store)
+//
+// Dependencies captured by the job function are not detected by reflection;
+// declare them when registering the job.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// OverlapPolicy selects what happens when a run is due while the previous one
+// is still running.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the run that is due. It is the default.
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue starts the run that is due as soon as the previous one
+	// returns. At most one run is queued.
+	OverlapQueue OverlapPolicy = "queue"
+)
+
+// Option configures a job.
+type Option func(*Job)
+
+// WithOverlap sets the policy for runs that are due while the previous run is
+// still running. Runs never overlap.
+func WithOverlap(policy OverlapPolicy) Option {
+	return func(j *Job) {
+		j.overlap = policy
+	}
+}
+
+// WithJitter delays every scheduled run by a random duration below max, so
+// that replicas do not run at the same instant.
+func WithJitter(max time.Duration) Option {
+	return func(j *Job) {
+		j.jitter = max
+	}
+}
+
+// WithReadyOnStart reports the job ready as soon as it starts, and runs it
+// first at the first scheduled time. By default the job runs once when it
+// starts and is ready after its first successful run.
+func WithReadyOnStart() Option {
+	return func(j *Job) {
+		j.readyOnStart = true
+	}
+}
+
+// Job is a goscade.Component running a function on a schedule.
+type Job struct {
+	schedule     Schedule
+	run          func(ctx context.Context) error
+	overlap      OverlapPolicy
+	jitter       time.Duration
+	readyOnStart bool
+}
+
+// New returns a job calling run on schedule. Errors returned by run are
+// logged and the job keeps its schedule; a panic in run fails the component.
+// The context passed to run is done once the job is asked to stop.
+func New(schedule Schedule, run func(ctx context.Context) error, opts ...Option) *Job {
+	j := &Job{schedule: schedule, run: run, overlap: OverlapSkip}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Run schedules the job until ctx is done and waits for the current run to
+// return.
+func (j *Job) Run(ctx context.Context, readinessProbe func(cause error)) error {
+	log := goscade.Logger(ctx)
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var ready sync.Once
+	markReady := func() {
+		ready.Do(func() { readinessProbe(nil) })
+	}
+
+	var running atomic.Bool
+	trigger := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-trigger:
+			}
+
+			running.Store(true)
+			err := goscade.Recover(ctx, j, func() error { return j.run(ctx) })
+			running.Store(false)
+			switch {
+			case err == nil:
+				markReady()
+			case ctx.Err() != nil:
+			case errors.As(err, new(*goscade.PanicError)):
+				cancel(err)
+				return
+			default:
+				log.Error("job failed", goscade.LogKeyError, err)
+			}
+		}
+	}()
+
+	if j.readyOnStart {
+		markReady()
+	} else {
+		trigger <- struct{}{}
+	}
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	defer timer.Stop()
+	next := time.Now()
+	for {
+		if next = j.schedule.Next(next); next.Before(time.Now()) {
+			next = j.schedule.Next(time.Now())
+		}
+		if next.IsZero() {
+			<-ctx.Done()
+			break
+		}
+
+		delay := time.Until(next)
+		if j.jitter > 0 {
+			delay += rand.N(j.jitter)
+		}
+		timer.Reset(delay)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if running.Load() && j.overlap == OverlapSkip {
+			log.Warn("job still running, skipping scheduled run")
+			continue
+		}
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+
+	<-done
+	if err := context.Cause(ctx); errors.As(err, new(*goscade.PanicError)) {
+		return err
+	}
+	return nil
+}
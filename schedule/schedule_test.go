@@ -0,0 +1,184 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+// eventLog records events in order.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+type database struct {
+	events *eventLog
+}
+
+func (d *database) Run(ctx context.Context, probe func(error)) error {
+	time.Sleep(20 * time.Millisecond)
+	d.events.add("database ready")
+	probe(nil)
+	<-ctx.Done()
+	d.events.add("database stopped")
+	return nil
+}
+
+func TestJob_RunsAfterDependenciesAndStopsBeforeThem(t *testing.T) {
+	events := &eventLog{}
+	db := &database{events: events}
+	var runs atomic.Int32
+	job := New(Every(5*time.Millisecond), func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			events.add("job ran")
+		}
+		return nil
+	})
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(db)
+	lc.Register(job, db)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []string{"database ready", "job ran", "database stopped"}, events.snapshot())
+}
+
+func TestJob_ReadyAfterFirstSuccessfulRun(t *testing.T) {
+	var runs atomic.Int32
+	job := New(Every(5*time.Millisecond), func(ctx context.Context) error {
+		if runs.Add(1) < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	var buf testutil.SyncBuffer
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&buf, nil)))
+	lc.Register(job)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	assert.GreaterOrEqual(t, runs.Load(), int32(3))
+	assert.Contains(t, buf.String(), `msg="job failed"`)
+	assert.Contains(t, buf.String(), "error=\"not yet\"")
+	cancel()
+	<-done
+}
+
+func TestJob_ReadyOnStart(t *testing.T) {
+	var runs atomic.Int32
+	job := New(Every(time.Hour), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithReadyOnStart())
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(job)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	cancel()
+	<-done
+	assert.Zero(t, runs.Load())
+}
+
+func runOverlapping(t *testing.T, policy OverlapPolicy) string {
+	t.Helper()
+	var runs, concurrent, maxConcurrent atomic.Int32
+	release := make(chan struct{})
+	job := New(Every(2*time.Millisecond), func(ctx context.Context) error {
+		n := concurrent.Add(1)
+		defer concurrent.Add(-1)
+		if n > maxConcurrent.Load() {
+			maxConcurrent.Store(n)
+		}
+		if runs.Add(1) == 2 {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+		}
+		return nil
+	}, WithOverlap(policy))
+
+	var buf testutil.SyncBuffer
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&buf, nil)))
+	lc.Register(job)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	require.Eventually(t, func() bool { return runs.Load() >= 4 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, int32(1), maxConcurrent.Load())
+	return buf.String()
+}
+
+func TestJob_OverlapSkip(t *testing.T) {
+	logs := runOverlapping(t, OverlapSkip)
+	assert.Contains(t, logs, "skipping scheduled run")
+}
+
+func TestJob_OverlapQueue(t *testing.T) {
+	logs := runOverlapping(t, OverlapQueue)
+	assert.NotContains(t, logs, "skipping scheduled run")
+}
+
+func TestJob_StopsOnShutdown(t *testing.T) {
+	started := make(chan struct{})
+	var canceled atomic.Bool
+	job := New(Every(time.Millisecond), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		canceled.Store(true)
+		return ctx.Err()
+	}, WithReadyOnStart(), WithJitter(time.Millisecond))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(job)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	<-started
+	cancel()
+	err := <-done
+	assert.Empty(t, goscade.ComponentErrors(err))
+	assert.True(t, canceled.Load())
+}
+
+func TestJob_PanicFailsComponent(t *testing.T) {
+	job := New(Every(time.Millisecond), func(ctx context.Context) error {
+		panic("boom")
+	}, WithReadyOnStart())
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(job)
+	err := lc.Run(context.Background(), nil)
+
+	var panicErr *goscade.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, "*schedule.Job", panicErr.Name)
+}
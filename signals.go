@@ -0,0 +1,115 @@
+package goscade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// defaultShutdownSignals are the signals handled by WithShutdownHook.
+var defaultShutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// SignalError is the shutdown cause when the lifecycle is stopped by a signal.
+// It matches context.Canceled with errors.Is, like a cancelled context.
+type SignalError struct {
+	// Signal is the signal that was received.
+	Signal os.Signal
+}
+
+// Error returns the name of the received signal.
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("received signal %v", e.Signal)
+}
+
+// Unwrap returns context.Canceled.
+func (e *SignalError) Unwrap() error {
+	return context.Canceled
+}
+
+// watchSignals dispatches the signals the lifecycle handles until stop is
+// called. The returned forceCtx is cancelled with a ForcedShutdownError when
+// a shutdown signal is received while the lifecycle is already stopping.
+func (lc *lifecycle) watchSignals(r *runState) (forceCtx context.Context, stop func()) {
+	forceCtx, force := context.WithCancelCause(context.Background())
+	stopSignals := lc.notifySignals(r, force)
+	return forceCtx, func() {
+		stopSignals()
+		force(context.Canceled)
+	}
+}
+
+// notifySignals subscribes to every signal the lifecycle handles and starts
+// dispatching them until the returned stop function is called.
+// A shutdown signal cancels the lifecycle context with a *SignalError; a
+// shutdown signal received while the lifecycle is already stopping forces
+// Run to return.
+func (lc *lifecycle) notifySignals(r *runState, force context.CancelCauseFunc) (stop func()) {
+	kinds := make(map[os.Signal]string)
+	for _, sig := range lc.statusDumpSignals {
+		kinds[sig] = "dump"
+	}
+	if lc.reloadHook != nil {
+		kinds[syscall.SIGHUP] = "reload"
+	}
+	if lc.shutdownHook {
+		for _, sig := range lc.shutdownSignals {
+			kinds[sig] = "shutdown"
+		}
+	}
+	if len(kinds) == 0 {
+		return func() {}
+	}
+
+	sigs := make([]os.Signal, 0, len(kinds))
+	for sig := range kinds {
+		sigs = append(sigs, sig)
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-sigCh:
+				switch kinds[sig] {
+				case "shutdown":
+					if r.lifecycleCtx.Err() != nil {
+						lc.log.Warn("signal received while stopping, forcing shutdown", "signal", sig)
+						force(fmt.Errorf("%w: %w", ForcedShutdownError, &SignalError{Signal: sig}))
+						continue
+					}
+					lc.log.Info("signal received, shutting down", "signal", sig)
+					r.lifecycleCtxCancel(&SignalError{Signal: sig})
+				case "reload":
+					lc.log.Info("signal received, reloading", "signal", sig)
+					go lc.reloadOnSignal()
+				case "dump":
+					lc.logStatus(r)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
+
+// logStatus logs the lifecycle status, the status of every component and the
+// dependency graph of r. It does not inspect the components, which may be
+// writing to their fields.
+func (lc *lifecycle) logStatus(r *runState) {
+	dependencies := lc.dependencies(r.compToParents)
+	snapshot := lc.snapshot(lc.Status(), dependencies, r.compStates)
+	lc.log.Info("lifecycle status", "status", snapshot.Status)
+	for _, comp := range snapshot.Components {
+		lc.log.Info("component status", LogKeyComponent, comp.Name, "status", comp.Status)
+	}
+	lc.log.Info("dependency graph", "dot", lc.buildGraph(dependencies).ToDOT())
+}
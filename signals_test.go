@@ -0,0 +1,156 @@
+//go:build unix
+
+package goscade
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+func sendSignal(t *testing.T, sig syscall.Signal) {
+	t.Helper()
+	require.NoError(t, syscall.Kill(syscall.Getpid(), sig))
+}
+
+func newSignalComponent(run func(context.Context) error) *lifecycleErrorComponent {
+	return &lifecycleErrorComponent{name: "server", run: func(ctx context.Context, probe func(error)) error {
+		probe(nil)
+		<-ctx.Done()
+		return run(ctx)
+	}}
+}
+
+func TestLifecycle_WithSignals(t *testing.T) {
+	lc := NewLifecycle(&mockLogger{}, WithSignals(syscall.SIGUSR1))
+	lc.Register(newSignalComponent(func(context.Context) error { return nil }))
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGUSR1)
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, context.Canceled)
+	var signalErr *SignalError
+	require.ErrorAs(t, runErr, &signalErr)
+	assert.Equal(t, syscall.SIGUSR1, signalErr.Signal)
+	assert.EqualError(t, runErr, "received signal user defined signal 1")
+}
+
+func TestLifecycle_SecondSignalForcesShutdown(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	lc := NewLifecycle(&mockLogger{}, WithSignals(syscall.SIGUSR1))
+	lc.Register(newSignalComponent(func(context.Context) error {
+		<-release
+		return nil
+	}))
+
+	ready, done := runLifecycleForErrors(lc, context.Background())
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGUSR1)
+	require.Eventually(t, func() bool {
+		return lc.Status() == LifecycleStatusStopping
+	}, time.Second, time.Millisecond)
+	sendSignal(t, syscall.SIGUSR1)
+
+	runErr := receiveLifecycleError(t, done)
+	assert.ErrorIs(t, runErr, ForcedShutdownError)
+	assert.NotErrorIs(t, runErr, ShutdownTimeoutError)
+	var signalErr *SignalError
+	require.ErrorAs(t, runErr, &signalErr)
+	assert.Equal(t, syscall.SIGUSR1, signalErr.Signal)
+}
+
+func TestLifecycle_WithReloadHook(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+	lc := NewLifecycle(&mockLogger{}, WithReloadHook(func() { reloaded <- struct{}{} }))
+	lc.Register(newSignalComponent(func(context.Context) error { return nil }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGHUP)
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("reload hook was not called")
+	}
+	assert.Equal(t, LifecycleStatusReady, lc.Status())
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+func TestLifecycle_WithStatusDump(t *testing.T) {
+	log := &testutil.RecordingLogger{}
+	lc := NewLifecycle(log, WithStatusDump(syscall.SIGUSR2))
+	lc.Register(newSignalComponent(func(context.Context) error { return nil }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGUSR2)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(log.String(), "digraph")
+	}, time.Second, time.Millisecond)
+	assert.Contains(t, log.String(), "lifecycle status lifecycle_id=")
+	assert.Contains(t, log.String(), "status=ready")
+	assert.Contains(t, log.String(), "component status lifecycle_id=")
+	assert.Contains(t, log.String(), "component=server status=ready")
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+func TestLifecycle_StatusDumpWhileComponentsMutateFields(t *testing.T) {
+	log := &testutil.RecordingLogger{}
+	lc := NewLifecycle(log, WithStatusDump(syscall.SIGUSR2))
+	database := recordedComponent("database", &eventRecorder{})
+	lc.Register(&mutatingComponent{database: database})
+	lc.Register(database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGUSR2)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(log.String(), "digraph")
+	}, time.Second, time.Millisecond)
+	assert.Contains(t, log.String(), `"database" -> `)
+	cancel()
+	receiveLifecycleError(t, done)
+}
+
+func TestLifecycle_SIGHUPReloadsReloaders(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+	lc := NewLifecycle(&mockLogger{}, WithReloadHook(func() {}))
+	lc.Register(&reloadingComponent{
+		lifecycleErrorComponent: *recordedComponent("database", &eventRecorder{}),
+		reload: func(context.Context) error {
+			reloaded <- struct{}{}
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready, done := runLifecycleForErrors(lc, ctx)
+	require.NoError(t, receiveLifecycleError(t, ready))
+	sendSignal(t, syscall.SIGHUP)
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("component was not reloaded")
+	}
+	cancel()
+	assert.Empty(t, ComponentErrors(receiveLifecycleError(t, done)))
+}
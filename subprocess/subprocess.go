@@ -0,0 +1,321 @@
+// Package subprocess provides a goscade component that runs an external
+// executable, such as a sidecar proxy.
+//
+// The process is ready once its readiness conditions hold, its output is
+// forwarded line by line to the component's logger (see goscade.Logger),
+// splitting lines longer than 64 KiB, and it is stopped with SIGTERM, then
+// SIGKILL shortly before the component's shutdown budget is spent (see
+// WithKillGracePeriod). Windows cannot deliver SIGTERM, so there the process is
+// killed right away. An exit the lifecycle did not ask for fails the component.
+//
+//	envoy := subprocess.New("envoy", []string{"-c", "envoy.yaml"},
+//		subprocess.WithHTTPReadiness("http://127.0.0.1:9901/ready"),
+//		subprocess.WithShutdownTimeout(10*time.Second),
+//	)
+//	lc.Register(envoy)
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// Option configures a process.
+type Option func(*Process)
+
+// WithEnv adds variables, in the form "KEY=value", to the environment the
+// process inherits.
+func WithEnv(env ...string) Option {
+	return func(p *Process) {
+		p.env = append(p.env, env...)
+	}
+}
+
+// WithDir sets the working directory of the process.
+func WithDir(dir string) Option {
+	return func(p *Process) {
+		p.dir = dir
+	}
+}
+
+// WithTCPReadiness makes the process ready only once address accepts TCP
+// connections.
+func WithTCPReadiness(address string) Option {
+	return withCheck(func(ctx context.Context) bool {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	})
+}
+
+// WithHTTPReadiness makes the process ready only once a GET request to url
+// returns 200 OK.
+func WithHTTPReadiness(url string) Option {
+	return withCheck(func(ctx context.Context) bool {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return false
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	})
+}
+
+// WithLogReadiness makes the process ready only once it has written a line
+// matching pattern to stdout or stderr.
+func WithLogReadiness(pattern *regexp.Regexp) Option {
+	return func(p *Process) {
+		p.logPattern = pattern
+	}
+}
+
+// WithProbeInterval sets how often the readiness conditions are checked. The
+// default is 100ms.
+func WithProbeInterval(interval time.Duration) Option {
+	return func(p *Process) {
+		p.probeInterval = interval
+	}
+}
+
+// WithShutdownTimeout gives the process its own shutdown budget instead of
+// the lifecycle shutdown timeout. See goscade.ShutdownTimeoutProvider.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(p *Process) {
+		p.shutdownTimeout = timeout
+	}
+}
+
+// WithKillGracePeriod sets how long before the end of the shutdown budget the
+// process is killed if it has not exited after SIGTERM, so that it is gone
+// before the lifecycle stops waiting for it. The default is 100ms.
+func WithKillGracePeriod(period time.Duration) Option {
+	return func(p *Process) {
+		p.killGracePeriod = period
+	}
+}
+
+func withCheck(check func(ctx context.Context) bool) Option {
+	return func(p *Process) {
+		p.checks = append(p.checks, check)
+	}
+}
+
+// Process is a goscade.Component running an external executable.
+type Process struct {
+	name string
+	args []string
+	env  []string
+	dir  string
+
+	checks          []func(ctx context.Context) bool
+	logPattern      *regexp.Regexp
+	probeInterval   time.Duration
+	shutdownTimeout time.Duration
+	killGracePeriod time.Duration
+
+	pid atomic.Int64
+}
+
+// New returns a component running the executable name with args. name is
+// resolved like exec.Command does. Without readiness options, the process is
+// ready once it has started.
+func New(name string, args []string, opts ...Option) *Process {
+	p := &Process{
+		name:            name,
+		args:            args,
+		probeInterval:   100 * time.Millisecond,
+		killGracePeriod: 100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ShutdownTimeout returns the budget set with WithShutdownTimeout.
+func (p *Process) ShutdownTimeout() time.Duration {
+	return p.shutdownTimeout
+}
+
+// Pid returns the process ID, or 0 if the process is not running.
+func (p *Process) Pid() int {
+	return int(p.pid.Load())
+}
+
+// Run starts the process, reports readiness once its readiness conditions
+// hold and waits until ctx is done or the process exits. On shutdown it sends
+// SIGTERM and waits for the process to exit within goscade.ShutdownContext,
+// then kills it the kill grace period before that context expires.
+func (p *Process) Run(ctx context.Context, readinessProbe func(cause error)) error {
+	log := goscade.Logger(ctx)
+
+	var logMatched atomic.Bool
+	cmd := exec.Command(p.name, p.args...)
+	cmd.Dir = p.dir
+	if len(p.env) > 0 {
+		cmd.Env = append(os.Environ(), p.env...)
+	}
+	stdout := p.output(log, "stdout", &logMatched)
+	stderr := p.output(log, "stderr", &logMatched)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		err = fmt.Errorf("start %s: %w", p.name, err)
+		readinessProbe(err)
+		return err
+	}
+	p.pid.Store(int64(cmd.Process.Pid))
+	defer p.pid.Store(0)
+	log.Info("process started", "pid", cmd.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		stdout.flush()
+		stderr.flush()
+		exited <- err
+	}()
+
+	probeCtx, cancelProbe := context.WithCancel(ctx)
+	defer cancelProbe()
+	var probing sync.WaitGroup
+	probing.Add(1)
+	go func() {
+		defer probing.Done()
+		if p.awaitReady(probeCtx, &logMatched) {
+			readinessProbe(nil)
+		}
+	}()
+
+	select {
+	case err := <-exited:
+		cancelProbe()
+		probing.Wait()
+		if err == nil {
+			return fmt.Errorf("process %s exited: %w", p.name, goscade.UnexpectedCloseComponentError)
+		}
+		return fmt.Errorf("process %s exited: %w", p.name, err)
+	case <-ctx.Done():
+	}
+	cancelProbe()
+	probing.Wait()
+
+	stopCtx, cancel := goscade.ShutdownContext(ctx)
+	defer cancel()
+	if deadline, ok := stopCtx.Deadline(); ok {
+		var cancelKill context.CancelFunc
+		stopCtx, cancelKill = context.WithDeadline(stopCtx, deadline.Add(-p.killGracePeriod))
+		defer cancelKill()
+	}
+	if err := terminate(cmd.Process); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		log.Warn("failed to terminate process", goscade.LogKeyError, err)
+	}
+	select {
+	case <-exited:
+		log.Info("process stopped")
+		return nil
+	case <-stopCtx.Done():
+	}
+
+	_ = cmd.Process.Kill()
+	<-exited
+	return fmt.Errorf("process %s killed: %w", p.name, stopCtx.Err())
+}
+
+// awaitReady polls the readiness conditions until all of them hold. It
+// returns false if ctx is done first.
+func (p *Process) awaitReady(ctx context.Context, logMatched *atomic.Bool) bool {
+	ticker := time.NewTicker(p.probeInterval)
+	defer ticker.Stop()
+	for {
+		ready := p.logPattern == nil || logMatched.Load()
+		for _, check := range p.checks {
+			if !ready {
+				break
+			}
+			ready = check(ctx)
+		}
+		if ready {
+			return ctx.Err() == nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// output returns a writer logging every line written to it and matching it
+// against the log readiness pattern.
+func (p *Process) output(log *slog.Logger, stream string, matched *atomic.Bool) *lineWriter {
+	return &lineWriter{line: func(line []byte) {
+		log.Info("process output", "stream", stream, "line", string(line))
+		if p.logPattern != nil && !matched.Load() && p.logPattern.Match(line) {
+			matched.Store(true)
+		}
+	}}
+}
+
+// maxLineLength is the longest line lineWriter buffers. Longer lines are
+// passed on in pieces of this length.
+const maxLineLength = 64 << 10
+
+// lineWriter calls line for every complete line written to it. flush passes
+// the last line on if it does not end with a newline.
+type lineWriter struct {
+	line func(line []byte)
+	buf  []byte
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 || i > maxLineLength {
+			// Pass on a partial line rather than buffer an unterminated one
+			if len(w.buf) < maxLineLength {
+				break
+			}
+			w.line(w.buf[:maxLineLength])
+			w.buf = w.buf[maxLineLength:]
+			continue
+		}
+		w.line(bytes.TrimRight(w.buf[:i], "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// flush calls line with the buffered incomplete line, if any. It must not be
+// called concurrently with Write.
+func (w *lineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.line(bytes.TrimRight(w.buf, "\r"))
+		w.buf = nil
+	}
+}
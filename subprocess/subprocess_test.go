@@ -0,0 +1,183 @@
+package subprocess
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+const helperEnv = "GOSCADE_SUBPROCESS_HELPER"
+
+// TestMain runs the test binary as the managed process when helperEnv is set.
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(helperEnv); mode != "" {
+		runHelper(mode, os.Args[len(os.Args)-1])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runHelper(mode, addr string) {
+	terms := make(chan os.Signal, 1)
+	signal.Notify(terms, syscall.SIGTERM)
+	switch mode {
+	case "log":
+		fmt.Println("booting")
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintln(os.Stderr, "server listening")
+	case "tcp":
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			os.Exit(2)
+		}
+		defer ln.Close()
+	case "http":
+		go func() {
+			_ = http.ListenAndServe(addr, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+		}()
+	case "stubborn":
+		signal.Ignore(syscall.SIGTERM)
+		fmt.Println("ignoring SIGTERM")
+		select {}
+	case "crash":
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(os.Stderr, "fatal: no config")
+		os.Exit(3)
+	}
+	<-terms
+	fmt.Println("terminated")
+}
+
+func helper(mode, addr string, opts ...Option) *Process {
+	opts = append([]Option{WithEnv(helperEnv + "=" + mode), WithProbeInterval(5 * time.Millisecond)}, opts...)
+	return New(os.Args[0], []string{"-test.run=^$", addr}, opts...)
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestProcess_LogReadinessAndOutput(t *testing.T) {
+	var buf testutil.SyncBuffer
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&buf, nil)))
+	p := helper("log", "", WithLogReadiness(regexp.MustCompile(`listening`)))
+	lc.Register(p)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	assert.NotZero(t, p.Pid())
+	assert.Contains(t, buf.String(), `stream=stdout line=booting`)
+	assert.Contains(t, buf.String(), `stream=stderr line="server listening"`)
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+	assert.Zero(t, p.Pid())
+	assert.Contains(t, buf.String(), `line=terminated`)
+	assert.Contains(t, buf.String(), `msg="process stopped"`)
+}
+
+func TestProcess_TCPReadiness(t *testing.T) {
+	addr := freeAddr(t)
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&testutil.SyncBuffer{}, nil)))
+	lc.Register(helper("tcp", addr, WithTCPReadiness(addr)))
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+}
+
+func TestProcess_HTTPReadiness(t *testing.T) {
+	addr := freeAddr(t)
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&testutil.SyncBuffer{}, nil)))
+	lc.Register(helper("http", addr, WithHTTPReadiness("http://"+addr+"/ready")))
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	resp, err := http.Get("http://" + addr + "/ready")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+}
+
+func TestProcess_KilledAfterShutdownBudget(t *testing.T) {
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&testutil.SyncBuffer{}, nil)))
+	lc.Register(helper("stubborn", "",
+		WithLogReadiness(regexp.MustCompile(`ignoring SIGTERM`)),
+		WithShutdownTimeout(200*time.Millisecond),
+	))
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	start := time.Now()
+	cancel()
+	err := <-done
+	assert.Less(t, time.Since(start), 5*time.Second)
+	// The process is killed before the budget is spent
+	assert.NotErrorIs(t, err, goscade.ShutdownTimeoutError)
+	componentErrs := goscade.ComponentErrors(err)
+	require.NotEmpty(t, componentErrs)
+	for _, componentErr := range componentErrs {
+		assert.Equal(t, goscade.PhaseShutdown, componentErr.Phase)
+	}
+}
+
+func TestProcess_UnexpectedExitFails(t *testing.T) {
+	var buf testutil.SyncBuffer
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&buf, nil)))
+	lc.Register(helper("crash", ""))
+	_, done := testutil.RunLifecycle(t, lc)
+
+	err := <-done
+	componentErrs := goscade.ComponentErrors(err)
+	require.Len(t, componentErrs, 1)
+	assert.Equal(t, goscade.PhaseRun, componentErrs[0].Phase)
+	assert.ErrorContains(t, err, "exit status 3")
+	// The last line has no trailing newline
+	assert.Contains(t, buf.String(), `stream=stderr line="fatal: no config"`)
+}
+
+func TestProcess_StartError(t *testing.T) {
+	lc := goscade.NewLifecycle(nil, goscade.WithLogHandler(slog.NewTextHandler(&testutil.SyncBuffer{}, nil)))
+	lc.Register(New("/nonexistent/binary", nil))
+
+	err := lc.Run(context.Background(), nil)
+	componentErrs := goscade.ComponentErrors(err)
+	require.Len(t, componentErrs, 1)
+	assert.Equal(t, goscade.PhaseReadiness, componentErrs[0].Phase)
+	assert.ErrorContains(t, err, "start /nonexistent/binary")
+}
+
+func TestLineWriter_SplitsLongLines(t *testing.T) {
+	var lines []string
+	w := &lineWriter{line: func(line []byte) { lines = append(lines, string(line)) }}
+	long := strings.Repeat("x", maxLineLength+10)
+	for i := 0; i < len(long); i += 1000 {
+		_, err := w.Write([]byte(long[i:min(i+1000, len(long))]))
+		require.NoError(t, err)
+		assert.Less(t, len(w.buf), maxLineLength)
+	}
+	_, err := w.Write([]byte("y\r\nshort\npartial"))
+	require.NoError(t, err)
+	w.flush()
+
+	assert.Equal(t, []string{long[:maxLineLength], long[maxLineLength:] + "y", "short", "partial"}, lines)
+}
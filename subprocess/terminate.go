@@ -0,0 +1,13 @@
+//go:build !windows
+
+package subprocess
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminate asks process to exit.
+func terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,8 @@
+package subprocess
+
+import "os"
+
+// terminate kills process: Windows cannot deliver SIGTERM.
+func terminate(process *os.Process) error {
+	return process.Kill()
+}
@@ -0,0 +1,139 @@
+// Package tracetest provides an in-memory goscade.Tracer for tests.
+//
+//	recorder := tracetest.NewRecorder()
+//	lc := goscade.NewLifecycle(log, goscade.WithTracer(recorder))
+//	// ... run the lifecycle ...
+//	for _, span := range recorder.Ended() {
+//		fmt.Println(span.Name, span.Duration())
+//	}
+package tracetest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// SpanStub is a snapshot of a recorded span.
+type SpanStub struct {
+	// ID identifies the span within the recorder, starting at 1.
+	ID int
+	// ParentID is the ID of the parent span, or 0 for a root span.
+	ParentID int
+	// Name is the name of the span.
+	Name string
+	// Attributes are the attributes the span was started with.
+	Attributes []goscade.Attribute
+	// Links are the IDs of the spans the span is linked to.
+	Links []int
+	// StartTime is when the span started.
+	StartTime time.Time
+	// EndTime is when the span ended, or zero if it has not ended.
+	EndTime time.Time
+	// Err is the error recorded with SetError, if any.
+	Err error
+}
+
+// Duration returns how long the span lasted.
+func (s SpanStub) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Attribute returns the value of the attribute with the given key.
+func (s SpanStub) Attribute(key string) (string, bool) {
+	for _, attr := range s.Attributes {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Recorder is a goscade.Tracer that keeps every span in memory.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*span
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type spanKey struct{}
+
+// Start starts a span that is a child of the recorded span in ctx, if any.
+func (r *Recorder) Start(ctx context.Context, name string, config goscade.SpanConfig) (context.Context, goscade.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &span{
+		recorder: r,
+		stub: SpanStub{
+			ID:         len(r.spans) + 1,
+			Name:       name,
+			Attributes: append([]goscade.Attribute(nil), config.Attributes...),
+			StartTime:  time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*span); ok && parent.recorder == r {
+		s.stub.ParentID = parent.stub.ID
+	}
+	for _, link := range config.Links {
+		if linked, ok := link.(*span); ok && linked.recorder == r {
+			s.stub.Links = append(s.stub.Links, linked.stub.ID)
+		}
+	}
+	r.spans = append(r.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Spans returns every recorded span in the order they were started.
+func (r *Recorder) Spans() []SpanStub {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stubs := make([]SpanStub, 0, len(r.spans))
+	for _, s := range r.spans {
+		stubs = append(stubs, s.stub)
+	}
+	return stubs
+}
+
+// Ended returns the spans that have ended in the order they were started.
+func (r *Recorder) Ended() []SpanStub {
+	var ended []SpanStub
+	for _, stub := range r.Spans() {
+		if !stub.EndTime.IsZero() {
+			ended = append(ended, stub)
+		}
+	}
+	return ended
+}
+
+// Reset removes every recorded span.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.spans = nil
+	r.mu.Unlock()
+}
+
+type span struct {
+	recorder *Recorder
+	stub     SpanStub
+}
+
+func (s *span) SetError(err error) {
+	s.recorder.mu.Lock()
+	s.stub.Err = err
+	s.recorder.mu.Unlock()
+}
+
+func (s *span) End() {
+	s.recorder.mu.Lock()
+	if s.stub.EndTime.IsZero() {
+		s.stub.EndTime = time.Now()
+	}
+	s.recorder.mu.Unlock()
+}
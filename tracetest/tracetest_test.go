@@ -0,0 +1,163 @@
+package tracetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+type database struct{}
+
+func (*database) Run(ctx context.Context, probe func(error)) error {
+	time.Sleep(10 * time.Millisecond)
+	probe(nil)
+	<-ctx.Done()
+	return nil
+}
+
+type api struct {
+	db *database
+}
+
+func (*api) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	<-ctx.Done()
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+type failingAPI struct {
+	db *database
+}
+
+func (*failingAPI) Run(context.Context, func(error)) error {
+	return errors.New("bind: address already in use")
+}
+
+// spanTree indexes recorded spans by ID.
+type spanTree map[int]SpanStub
+
+func newSpanTree(spans []SpanStub) spanTree {
+	tree := make(spanTree, len(spans))
+	for _, span := range spans {
+		tree[span.ID] = span
+	}
+	return tree
+}
+
+// find returns the span with the given name under the given parent.
+func (tree spanTree) find(t *testing.T, parentID int, name string) SpanStub {
+	t.Helper()
+	for _, span := range tree {
+		if span.ParentID == parentID && span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q with parent %d not found", name, parentID)
+	return SpanStub{}
+}
+
+func runLifecycle(t *testing.T, lc goscade.Lifecycle) (readyErr, runErr error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() { done <- lc.Run(ctx, func(err error) { ready <- err }) }()
+
+	select {
+	case readyErr = <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("lifecycle did not start")
+	}
+	cancel()
+	select {
+	case runErr = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lifecycle did not stop")
+	}
+	return readyErr, runErr
+}
+
+func TestRecorder_TracesStartupAndShutdown(t *testing.T) {
+	recorder := NewRecorder()
+	lc := goscade.NewLifecycle(testutil.NopLogger{}, goscade.WithTracer(recorder))
+	db := &database{}
+	lc.Register(db)
+	lc.Register(&api{db: db})
+
+	readyErr, _ := runLifecycle(t, lc)
+	require.NoError(t, readyErr)
+	require.Len(t, recorder.Ended(), len(recorder.Spans()))
+	tree := newSpanTree(recorder.Spans())
+
+	start := tree.find(t, 0, goscade.SpanLifecycleStart)
+	dbStart := tree.find(t, start.ID, "*tracetest.database")
+	apiStart := tree.find(t, start.ID, "*tracetest.api")
+	name, ok := apiStart.Attribute(goscade.ComponentLabel)
+	assert.True(t, ok)
+	assert.Equal(t, "*tracetest.api", name)
+	assert.Equal(t, []int{dbStart.ID}, apiStart.Links)
+	assert.Empty(t, dbStart.Links)
+	assert.GreaterOrEqual(t, tree.find(t, dbStart.ID, goscade.SpanRun).Duration(), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, tree.find(t, apiStart.ID, goscade.SpanWait).Duration(), 10*time.Millisecond)
+	tree.find(t, apiStart.ID, goscade.SpanRun)
+	assert.False(t, start.EndTime.Before(apiStart.EndTime))
+
+	shutdown := tree.find(t, 0, goscade.SpanLifecycleShutdown)
+	dbShutdown := tree.find(t, shutdown.ID, "*tracetest.database")
+	apiShutdown := tree.find(t, shutdown.ID, "*tracetest.api")
+	assert.Equal(t, []int{apiShutdown.ID}, dbShutdown.Links)
+	assert.Empty(t, apiShutdown.Links)
+	assert.GreaterOrEqual(t, tree.find(t, dbShutdown.ID, goscade.SpanWait).Duration(), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, tree.find(t, apiShutdown.ID, goscade.SpanStop).Duration(), 10*time.Millisecond)
+	tree.find(t, dbShutdown.ID, goscade.SpanStop)
+	for _, span := range tree {
+		assert.NoError(t, span.Err, span.Name)
+	}
+}
+
+func TestRecorder_RecordsStartupErrors(t *testing.T) {
+	recorder := NewRecorder()
+	lc := goscade.NewLifecycle(testutil.NopLogger{}, goscade.WithTracer(recorder))
+	db := &database{}
+	lc.Register(db)
+	lc.Register(&failingAPI{db: db})
+
+	readyErr, _ := runLifecycle(t, lc)
+	require.Error(t, readyErr)
+	tree := newSpanTree(recorder.Spans())
+
+	start := tree.find(t, 0, goscade.SpanLifecycleStart)
+	assert.Error(t, start.Err)
+	apiStart := tree.find(t, start.ID, "*tracetest.failingAPI")
+	assert.EqualError(t, apiStart.Err, "bind: address already in use")
+	assert.EqualError(t, tree.find(t, apiStart.ID, goscade.SpanRun).Err, "bind: address already in use")
+	assert.False(t, apiStart.EndTime.IsZero())
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	recorder := NewRecorder()
+	ctx, parent := recorder.Start(context.Background(), "parent", goscade.SpanConfig{})
+	_, child := recorder.Start(ctx, "child", goscade.SpanConfig{Links: []goscade.Span{parent}})
+	child.SetError(errors.New("failed"))
+	child.End()
+
+	spans := recorder.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, SpanStub{ID: 1, Name: "parent", StartTime: spans[0].StartTime}, spans[0])
+	assert.Equal(t, 1, spans[1].ParentID)
+	assert.Equal(t, []int{1}, spans[1].Links)
+	assert.EqualError(t, spans[1].Err, "failed")
+	assert.Len(t, recorder.Ended(), 1)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Spans())
+}
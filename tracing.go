@@ -0,0 +1,248 @@
+package goscade
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Span names of the traces recorded by Run. Component spans are named after
+// the component and carry its name in the ComponentLabel attribute.
+const (
+	// SpanLifecycleStart covers the startup of all components, until the
+	// lifecycle is ready or fails to start.
+	SpanLifecycleStart = "lifecycle.start"
+	// SpanLifecycleShutdown covers the shutdown of all components.
+	SpanLifecycleShutdown = "lifecycle.shutdown"
+	// SpanWait is a child of a component span covering the time spent waiting
+	// for its dependencies to become ready during startup, or for its
+	// dependents to stop during shutdown.
+	SpanWait = "wait"
+	// SpanRun is a child of a component startup span covering the time from
+	// calling Run until the component reports readiness.
+	SpanRun = "run"
+	// SpanStop is a child of a component shutdown span covering the time from
+	// cancelling the component's context until it stops.
+	SpanStop = "stop"
+)
+
+// Attribute is a key-value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// SpanConfig describes a span to start.
+type SpanConfig struct {
+	// Attributes are attached to the span.
+	Attributes []Attribute
+	// Links are spans the new span is causally related to, other than its
+	// parent. Run links a component's startup span to the startup spans of its
+	// dependencies, and its shutdown span to the shutdown spans of its
+	// dependents.
+	Links []Span
+}
+
+// Tracer starts spans. It mirrors the subset of the OpenTelemetry tracing API
+// used by the lifecycle, so a bridge to an OpenTelemetry tracer only has to
+// map SpanConfig to span start options and resolve links to the span contexts
+// of the spans it returned. See WithTracer.
+type Tracer interface {
+	// Start starts a span that is a child of the span in ctx, if any, and
+	// returns a context holding the new span.
+	Start(ctx context.Context, name string, config SpanConfig) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetError records err and marks the span as failed.
+	SetError(err error)
+	// End completes the span.
+	End()
+}
+
+// noopTracer is the Tracer used when WithTracer is not set.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ SpanConfig) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetError(error) {}
+func (noopSpan) End()           {}
+
+// tracedSpan is a span that is ended exactly once, possibly from several
+// goroutines racing to report its outcome.
+type tracedSpan struct {
+	ctx  context.Context
+	span Span
+	once sync.Once
+}
+
+// startSpan starts a span named name for the component.
+func (lc *lifecycle) startSpan(ctx context.Context, name, compName string, links []Span) *tracedSpan {
+	config := SpanConfig{Links: links}
+	if compName != "" {
+		config.Attributes = []Attribute{{Key: ComponentLabel, Value: compName}}
+	}
+	ctx, span := lc.tracer.Start(ctx, name, config)
+	return &tracedSpan{ctx: ctx, span: span}
+}
+
+// end records err, if any, and ends the span unless it has already ended.
+// It is safe to call on a nil span.
+func (s *tracedSpan) end(err error) {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		if err != nil {
+			s.span.SetError(err)
+		}
+		s.span.End()
+	})
+}
+
+// childSpan starts a child span of parent, or returns nil if parent is nil.
+func (lc *lifecycle) childSpan(parent *tracedSpan, name string) *tracedSpan {
+	if parent == nil {
+		return nil
+	}
+	return lc.startSpan(parent.ctx, name, "", nil)
+}
+
+// shutdownSpan returns the shutdown span of comp, or nil if shutdown has not
+// started or comp had already stopped when it did.
+func (r *runState) shutdownSpan(comp Component) *tracedSpan {
+	if r.drainedCtx.Err() == nil {
+		return nil
+	}
+	return r.shutdownSpans[comp]
+}
+
+// shutdownChildSpan starts a child span of the shutdown span of comp. It
+// returns nil if there is no such span or the shutdown trace has ended.
+func (lc *lifecycle) shutdownChildSpan(r *runState, comp Component, name string) *tracedSpan {
+	parent := r.shutdownSpan(comp)
+	if parent == nil {
+		return nil
+	}
+
+	r.traceMu.Lock()
+	defer r.traceMu.Unlock()
+	if r.shutdownEnded {
+		return nil
+	}
+	span := lc.childSpan(parent, name)
+	r.shutdownChildren = append(r.shutdownChildren, span)
+	return span
+}
+
+// traceStart sets the startup span of gen. Manually started generations are
+// not part of the startup trace and have none.
+func (gen *componentGeneration) traceStart(r *runState, comp Component) {
+	if !gen.manual {
+		gen.startSpan = r.startSpans[comp]
+	}
+}
+
+// traceWait starts the span covering the wait of gen for its dependencies.
+func (lc *lifecycle) traceWait(gen *componentGeneration) *tracedSpan {
+	return lc.childSpan(gen.startSpan, SpanWait)
+}
+
+// traceRun starts the span covering the run of gen until it is ready.
+func (lc *lifecycle) traceRun(gen *componentGeneration) {
+	gen.runSpan = lc.childSpan(gen.startSpan, SpanRun)
+}
+
+// traceStarted ends the startup spans of gen, recording err if the
+// generation failed to become ready.
+func (gen *componentGeneration) traceStarted(err error) {
+	gen.runSpan.end(err)
+	gen.startSpan.end(err)
+}
+
+// traceStop starts the span covering the stop of gen.
+func (lc *lifecycle) traceStop(r *runState, comp Component, gen *componentGeneration) {
+	gen.stopSpan.Store(lc.shutdownChildSpan(r, comp, SpanStop))
+}
+
+// traceStopped ends the stop span of gen and the shutdown span of comp,
+// recording the shutdown timeout error, if any.
+func (r *runState) traceStopped(comp Component, gen *componentGeneration) {
+	timeoutErr := context.Cause(gen.teardownCtx)
+	if errors.Is(timeoutErr, context.Canceled) {
+		timeoutErr = nil
+	}
+	gen.stopSpan.Load().end(timeoutErr)
+	r.shutdownSpan(comp).end(timeoutErr)
+}
+
+// endShutdownTrace ends every span of the shutdown trace that is still open,
+// recording err on them.
+func (r *runState) endShutdownTrace(err error) {
+	r.traceMu.Lock()
+	r.shutdownEnded = true
+	children := r.shutdownChildren
+	r.traceMu.Unlock()
+
+	for _, span := range children {
+		span.end(err)
+	}
+	for _, span := range r.shutdownSpans {
+		span.end(err)
+	}
+	r.shutdownRoot.end(err)
+}
+
+// traceStart starts the lifecycle startup trace with a span for each
+// component, parent-first so that every span can link to its dependencies.
+func (lc *lifecycle) traceStart(ctx context.Context, r *runState) *tracedSpan {
+	root := lc.startSpan(ctx, SpanLifecycleStart, "", nil)
+	comps := make([]Component, 0, len(r.compStates))
+	for comp := range r.compStates {
+		comps = append(comps, comp)
+	}
+
+	r.startSpans = make(map[Component]*tracedSpan, len(comps))
+	for _, comp := range parentFirst(comps, r.compToParents) {
+		var links []Span
+		for parent := range r.compToParents[comp] {
+			if span, ok := r.startSpans[parent]; ok {
+				links = append(links, span.span)
+			}
+		}
+		r.startSpans[comp] = lc.startSpan(root.ctx, r.compStates[comp].componentName, r.compStates[comp].componentName, links)
+	}
+	return root
+}
+
+// traceShutdown starts the lifecycle shutdown trace with a span for each
+// component that is still running, child-first so that every span can link
+// to its dependents.
+func (lc *lifecycle) traceShutdown(ctx context.Context, r *runState) *tracedSpan {
+	root := lc.startSpan(ctx, SpanLifecycleShutdown, "", nil)
+	var comps []Component
+	for comp, state := range r.compStates {
+		if state.current().teardownCtx.Err() == nil {
+			comps = append(comps, comp)
+		}
+	}
+
+	ordered := parentFirst(comps, r.compToParents)
+	r.shutdownSpans = make(map[Component]*tracedSpan, len(comps))
+	for i := len(ordered) - 1; i >= 0; i-- {
+		comp := ordered[i]
+		var links []Span
+		for child := range r.compToChildren[comp] {
+			if span, ok := r.shutdownSpans[child]; ok {
+				links = append(links, span.span)
+			}
+		}
+		r.shutdownSpans[comp] = lc.startSpan(root.ctx, r.compStates[comp].componentName, r.compStates[comp].componentName, links)
+	}
+	return root
+}
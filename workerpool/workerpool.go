@@ -0,0 +1,261 @@
+// Package workerpool provides a goscade component that processes items with a
+// resizable pool of workers.
+//
+// Producers depend on the pool, so the lifecycle starts them after the
+// workers and stops them before the pool drains the remaining items:
+//
+//	jobs := make(chan Job, 100)
+//	pool := workerpool.New(jobs, handle, workerpool.WithWorkers(8))
+//	lc.Register(pool)
+//	lc.Register(producer, pool)
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ognick/goscade/v2"
+)
+
+// ErrClosed is returned by Run when the channel of items is closed while the
+// pool is running.
+var ErrClosed = errors.New("workerpool: items channel closed")
+
+// Option configures a pool.
+type Option func(*config)
+
+type config struct {
+	workers int
+}
+
+// WithWorkers sets the initial number of workers. The default is 1.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// Pool is a goscade.Component calling a handler for every item it receives.
+type Pool[T any] struct {
+	// next blocks until the next item is available. It returns false if the
+	// worker should stop.
+	next     func(ctx context.Context) (T, bool, error)
+	buffered func() (T, bool)
+	handle   func(ctx context.Context, item T) error
+
+	mu      sync.Mutex
+	size    int
+	run     *poolRun
+	workers []context.CancelFunc
+}
+
+// poolRun is the state of an active Run call.
+type poolRun struct {
+	// stopped is the context passed to Run, ctx is cancelled when the pool
+	// stops for any reason and handle is passed to the handler.
+	stopped  context.Context
+	ctx      context.Context
+	handle   context.Context
+	wg       sync.WaitGroup
+	failures chan error
+}
+
+// New returns a pool handling the items received from items. Run returns
+// ErrClosed if items is closed while the pool is running. On shutdown the
+// workers keep handling the items buffered in items until it is empty or the
+// shutdown budget is spent.
+func New[T any](items <-chan T, handle func(ctx context.Context, item T) error, opts ...Option) *Pool[T] {
+	p := newPool(handle, opts)
+	p.next = func(ctx context.Context) (T, bool, error) {
+		var zero T
+		select {
+		case <-ctx.Done():
+			return zero, false, nil
+		case item, ok := <-items:
+			if !ok && ctx.Err() == nil {
+				return zero, false, ErrClosed
+			}
+			return item, ok, nil
+		}
+	}
+	p.buffered = func() (T, bool) {
+		select {
+		case item, ok := <-items:
+			return item, ok
+		default:
+			var zero T
+			return zero, false
+		}
+	}
+	return p
+}
+
+// NewFromSource returns a pool handling the items returned by source, which
+// each worker calls in a loop. source must return once its context is done.
+// An error returned by source stops the pool and is returned by Run.
+func NewFromSource[T any](
+	source func(ctx context.Context) (T, error),
+	handle func(ctx context.Context, item T) error,
+	opts ...Option,
+) *Pool[T] {
+	p := newPool(handle, opts)
+	p.next = func(ctx context.Context) (T, bool, error) {
+		item, err := source(ctx)
+		if ctx.Err() != nil {
+			return item, false, nil
+		}
+		if err != nil {
+			return item, false, fmt.Errorf("workerpool: source: %w", err)
+		}
+		return item, true, nil
+	}
+	p.buffered = func() (T, bool) {
+		var zero T
+		return zero, false
+	}
+	return p
+}
+
+func newPool[T any](handle func(ctx context.Context, item T) error, opts []Option) *Pool[T] {
+	c := config{workers: 1}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &Pool[T]{handle: handle, size: max(c.workers, 0)}
+}
+
+// Run starts the workers, reports readiness and handles items until ctx is
+// done. It then waits for the items being handled and drains the buffered
+// ones within goscade.ShutdownContext; the context passed to the handler is
+// done once that budget is spent. Errors returned by the handler are logged.
+func (p *Pool[T]) Run(ctx context.Context, readinessProbe func(cause error)) error {
+	poolCtx, cancelPool := context.WithCancel(ctx)
+	defer cancelPool()
+	handleCtx, cancelHandle := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandle()
+
+	r := &poolRun{stopped: ctx, ctx: poolCtx, handle: handleCtx, failures: make(chan error, 1)}
+	p.mu.Lock()
+	p.run = r
+	for range p.size {
+		p.startWorker(r)
+	}
+	p.mu.Unlock()
+	readinessProbe(nil)
+
+	var err error
+	select {
+	case <-ctx.Done():
+		stopCtx, cancel := goscade.ShutdownContext(ctx)
+		defer cancel()
+		stop := context.AfterFunc(stopCtx, cancelHandle)
+		defer stop()
+	case err = <-r.failures:
+		cancelPool()
+	}
+
+	p.mu.Lock()
+	p.run = nil
+	p.workers = nil
+	p.mu.Unlock()
+	r.wg.Wait()
+	if err == nil {
+		select {
+		case err = <-r.failures:
+		default:
+		}
+	}
+	return err
+}
+
+// Resize changes the number of workers. Removed workers finish the item they
+// are handling first. Resizing to zero pauses the pool.
+func (p *Pool[T]) Resize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("workerpool: invalid size %d", n)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.size = n
+	if p.run == nil {
+		return nil
+	}
+	for len(p.workers) < n {
+		p.startWorker(p.run)
+	}
+	for len(p.workers) > n {
+		last := len(p.workers) - 1
+		p.workers[last]()
+		p.workers = p.workers[:last]
+	}
+	return nil
+}
+
+// Size returns the number of workers.
+func (p *Pool[T]) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// startWorker starts a worker. It must be called with p.mu held.
+func (p *Pool[T]) startWorker(r *poolRun) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	p.workers = append(p.workers, cancel)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer cancel()
+		if err := p.work(ctx, r); err != nil {
+			select {
+			case r.failures <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// work handles items until ctx is done, then drains the buffered items if
+// the pool is shutting down. It returns a failure that stops the pool.
+func (p *Pool[T]) work(ctx context.Context, r *poolRun) error {
+	log := goscade.Logger(ctx)
+	process := func(item T) error {
+		err := goscade.Recover(r.handle, p, func() error { return p.handle(r.handle, item) })
+		if err != nil && !errors.As(err, new(*goscade.PanicError)) {
+			log.Error("item failed", goscade.LogKeyError, err)
+			return nil
+		}
+		return err
+	}
+
+	for {
+		item, ok, err := p.next(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		if err := process(item); err != nil {
+			return err
+		}
+	}
+
+	// Drain only on shutdown, not when removed by Resize or on a failure
+	if r.stopped.Err() == nil {
+		return nil
+	}
+	for r.handle.Err() == nil {
+		item, ok := p.buffered()
+		if !ok {
+			return nil
+		}
+		if err := process(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ognick/goscade/v2"
+	"github.com/ognick/goscade/v2/internal/testutil"
+)
+
+// producer sends items until it is stopped.
+type producer struct {
+	items   chan<- int
+	sent    atomic.Int32
+	stopped atomic.Bool
+}
+
+func (p *producer) Run(ctx context.Context, probe func(error)) error {
+	probe(nil)
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			p.stopped.Store(true)
+			return nil
+		case p.items <- i:
+			p.sent.Add(1)
+		}
+	}
+}
+
+// concurrency tracks how many handlers run at once.
+type concurrency struct {
+	current, peak atomic.Int32
+}
+
+func (c *concurrency) enter() {
+	n := c.current.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrency) leave() {
+	c.current.Add(-1)
+}
+
+func TestPool_HandlesItemsConcurrently(t *testing.T) {
+	items := make(chan int)
+	var c concurrency
+	var handled atomic.Int32
+	pool := New(items, func(ctx context.Context, item int) error {
+		c.enter()
+		defer c.leave()
+		time.Sleep(time.Millisecond)
+		handled.Add(1)
+		return nil
+	}, WithWorkers(4))
+	p := &producer{items: items}
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	lc.Register(p, pool)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	require.Eventually(t, func() bool { return handled.Load() >= 40 }, 5*time.Second, time.Millisecond)
+	cancel()
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+
+	assert.True(t, p.stopped.Load())
+	assert.Equal(t, int32(4), c.peak.Load())
+	assert.Equal(t, p.sent.Load(), handled.Load())
+}
+
+func TestPool_Resize(t *testing.T) {
+	items := make(chan int)
+	var c concurrency
+	pool := New(items, func(ctx context.Context, item int) error {
+		c.enter()
+		defer c.leave()
+		time.Sleep(time.Millisecond)
+		return nil
+	}, WithWorkers(1))
+	require.Error(t, pool.Resize(-1))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	lc.Register(&producer{items: items}, pool)
+	cancel, done := testutil.RunLifecycle(t, lc)
+
+	require.NoError(t, pool.Resize(6))
+	assert.Equal(t, 6, pool.Size())
+	require.Eventually(t, func() bool { return c.peak.Load() == 6 }, 5*time.Second, time.Millisecond)
+
+	require.NoError(t, pool.Resize(2))
+	time.Sleep(10 * time.Millisecond)
+	c.peak.Store(0)
+	time.Sleep(20 * time.Millisecond)
+	assert.LessOrEqual(t, c.peak.Load(), int32(2))
+
+	cancel()
+	<-done
+}
+
+func TestPool_DrainsBufferedItemsOnShutdown(t *testing.T) {
+	items := make(chan int, 10)
+	gate := make(chan struct{})
+	var handled atomic.Int32
+	pool := New(items, func(ctx context.Context, item int) error {
+		<-gate
+		handled.Add(1)
+		return nil
+	}, WithWorkers(2))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	for i := range 10 {
+		items <- i
+	}
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	close(gate)
+
+	assert.Empty(t, goscade.ComponentErrors(<-done))
+	assert.Equal(t, int32(10), handled.Load())
+}
+
+func TestPool_CancelsHandlersAfterShutdownBudget(t *testing.T) {
+	items := make(chan int, 1)
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	pool := New(items, func(ctx context.Context, item int) error {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{}, goscade.WithShutdownTimeout(50*time.Millisecond))
+	lc.Register(pool)
+	cancel, done := testutil.RunLifecycle(t, lc)
+	items <- 1
+	<-started
+	cancel()
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler context not cancelled after the shutdown budget")
+	}
+	<-done
+}
+
+func TestPool_ClosedChannelFails(t *testing.T) {
+	items := make(chan int)
+	pool := New(items, func(ctx context.Context, item int) error { return nil }, WithWorkers(3))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	_, done := testutil.RunLifecycle(t, lc)
+	close(items)
+
+	err := <-done
+	require.ErrorIs(t, err, ErrClosed)
+	assert.Equal(t, goscade.PhaseRun, goscade.ComponentErrors(err)[0].Phase)
+}
+
+func TestPool_HandlerPanicFails(t *testing.T) {
+	items := make(chan int, 1)
+	items <- 1
+	pool := New(items, func(ctx context.Context, item int) error { panic("boom") })
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	err := lc.Run(context.Background(), nil)
+
+	var panicErr *goscade.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, "*workerpool.Pool[int]", panicErr.Name)
+}
+
+func TestNewFromSource(t *testing.T) {
+	var mu sync.Mutex
+	next := 0
+	sourceErr := errors.New("queue gone")
+	source := func(ctx context.Context) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if next == 20 {
+			return 0, sourceErr
+		}
+		next++
+		return next, nil
+	}
+	var sum atomic.Int32
+	pool := NewFromSource(source, func(ctx context.Context, item int) error {
+		sum.Add(int32(item))
+		return nil
+	}, WithWorkers(3))
+
+	lc := goscade.NewLifecycle(testutil.NopLogger{})
+	lc.Register(pool)
+	err := lc.Run(context.Background(), nil)
+	require.ErrorIs(t, err, sourceErr)
+	assert.Equal(t, int32(20*21/2), sum.Load())
+}